@@ -0,0 +1,195 @@
+// Package merge implements a line based three-way merge, it is used to merge
+// the regenerated code into the files which have been modified by hand.
+package merge
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	markerCurrent   = "<<<<<<< current\n"
+	markerSeparator = "=======\n"
+	markerGenerated = ">>>>>>> generated\n"
+)
+
+// ThreeWay merges the changes from base to current and from base to generated,
+// the conflicting chunks are wrapped with git style conflict markers.
+func ThreeWay(base, current, generated []byte) ([]byte, bool) {
+	o, a, b := splitLines(base), splitLines(current), splitLines(generated)
+	matchA, matchB := match(o, a), match(o, b)
+
+	var (
+		out      []string
+		conflict bool
+		i        int
+		ia       int
+		ib       int
+	)
+	for i < len(o) || ia < len(a) || ib < len(b) {
+		// the line is not changed by either side.
+		if i < len(o) && matchA[i] == ia && matchB[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+			continue
+		}
+
+		// the lines before the next stable line form a changed chunk.
+		next := i
+		for next < len(o) && (matchA[next] < 0 || matchB[next] < 0) {
+			next++
+		}
+		endA, endB := len(a), len(b)
+		if next < len(o) {
+			endA, endB = matchA[next], matchB[next]
+		}
+
+		chunkO, chunkA, chunkB := o[i:next], a[ia:endA], b[ib:endB]
+		switch {
+		case equal(chunkA, chunkO):
+			out = append(out, chunkB...)
+		case equal(chunkB, chunkO), equal(chunkA, chunkB):
+			out = append(out, chunkA...)
+		default:
+			conflict = true
+			out = append(out, markerCurrent)
+			out = append(out, terminate(chunkA)...)
+			out = append(out, markerSeparator)
+			out = append(out, terminate(chunkB)...)
+			out = append(out, markerGenerated)
+		}
+		i, ia, ib = next, endA, endB
+	}
+
+	return []byte(strings.Join(out, "")), conflict
+}
+
+// match returns the index of the line in b which matches the line in a by a
+// shortest edit script, -1 means the line is not matched. It uses the linear
+// space variant of Myers' diff, the memory is proportional to len(a)+len(b).
+func match(a, b []string) []int {
+	ret := make([]int, len(a))
+	for i := range ret {
+		ret[i] = -1
+	}
+	size := (len(a)+len(b)+1)/2 + 1
+	d := differ{a: a, b: b, ret: ret, forward: make([]int, 2*size+1), backward: make([]int, 2*size+1)}
+	d.compare(0, len(a), 0, len(b))
+	return ret
+}
+
+type differ struct {
+	a, b []string
+	ret  []int
+	// forward and backward are the furthest reaching x of the diagonals,
+	// they are shared by the recursive calls.
+	forward, backward []int
+}
+
+// compare matches a[aLo:aHi] with b[bLo:bHi], the common prefix and suffix are
+// matched directly, the rest is split at the middle snake.
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ret[aLo] = bLo
+		aLo, bLo = aLo+1, bLo+1
+	}
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi, bHi = aHi-1, bHi-1
+		d.ret[aHi] = bHi
+	}
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+
+	x, y := d.middleSnake(aLo, aHi, bLo, bHi)
+	d.compare(aLo, x, bLo, y)
+	d.compare(x, aHi, y, bHi)
+}
+
+// middleSnake returns a point on a shortest edit script of a[aLo:aHi] and
+// b[bLo:bHi] by searching from both ends until the paths overlap.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	off := (len(d.forward) - 1) / 2
+	vf, vb := d.forward, d.backward
+	vf[off+1], vb[off+1] = 0, 0
+
+	for k := 0; k <= (n+m+1)/2; k++ {
+		for diag := -k; diag <= k; diag += 2 {
+			x := next(vf, off, diag, k)
+			y := x - diag
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x, y = x+1, y+1
+			}
+			vf[off+diag] = x
+			// the backward path on the diagonal has k-1 edits.
+			if c := delta - diag; odd && c >= -(k-1) && c <= k-1 && x+vb[off+c] >= n {
+				return aLo + x, bLo + y
+			}
+		}
+		for diag := -k; diag <= k; diag += 2 {
+			x := next(vb, off, diag, k)
+			y := x - diag
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x, y = x+1, y+1
+			}
+			vb[off+diag] = x
+			// the forward path on the diagonal has k edits.
+			if c := delta - diag; !odd && c >= -k && c <= k && x+vf[off+c] >= n {
+				return aHi - x, bHi - y
+			}
+		}
+	}
+	// unreachable, the paths always overlap within (n+m+1)/2 edits.
+	return aLo, bLo
+}
+
+// next returns the x where the path on the diagonal starts with k edits,
+// moving down from diag+1 or right from diag-1.
+func next(v []int, off, diag, k int) int {
+	if diag == -k || (diag != k && v[off+diag-1] < v[off+diag+1]) {
+		return v[off+diag+1]
+	}
+	return v[off+diag-1] + 1
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// terminate makes sure the last line ends with a newline, so that the
+// conflict markers always start at a new line.
+func terminate(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	ret := append([]string{}, lines...)
+	ret[len(ret)-1] += "\n"
+	return ret
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasConflict reports whether the data contains unresolved conflict markers.
+func HasConflict(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(markerCurrent)) || bytes.Contains(data, []byte("\n"+markerCurrent))
+}
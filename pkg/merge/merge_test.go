@@ -0,0 +1,146 @@
+package merge
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreeWay(t *testing.T) {
+	testData := []struct {
+		name      string
+		base      string
+		current   string
+		generated string
+		expect    string
+		conflict  bool
+	}{
+		{
+			name:      "unchanged",
+			base:      "a\nb\nc\n",
+			current:   "a\nb\nc\n",
+			generated: "a\nb\nc\n",
+			expect:    "a\nb\nc\n",
+		},
+		{
+			name:      "generated only",
+			base:      "a\nb\nc\n",
+			current:   "a\nb\nc\n",
+			generated: "a\nb\nb2\nc\n",
+			expect:    "a\nb\nb2\nc\n",
+		},
+		{
+			name:      "current only",
+			base:      "a\nb\nc\n",
+			current:   "a\nx\nb\nc\n",
+			generated: "a\nb\nc\n",
+			expect:    "a\nx\nb\nc\n",
+		},
+		{
+			name:      "both",
+			base:      "a\nb\nc\nd\n",
+			current:   "a\nx\nb\nc\nd\n",
+			generated: "a\nb\nc\ny\nd\n",
+			expect:    "a\nx\nb\nc\ny\nd\n",
+		},
+		{
+			name:      "same change",
+			base:      "a\nb\n",
+			current:   "a\nx\nb\n",
+			generated: "a\nx\nb\n",
+			expect:    "a\nx\nb\n",
+		},
+		{
+			name:      "append",
+			base:      "a\n",
+			current:   "a\n",
+			generated: "a\nb",
+			expect:    "a\nb",
+		},
+		{
+			name:      "conflict",
+			base:      "a\nb\nc\n",
+			current:   "a\nx\nc\n",
+			generated: "a\ny\nc\n",
+			expect:    "a\n<<<<<<< current\nx\n=======\ny\n>>>>>>> generated\nc\n",
+			conflict:  true,
+		},
+	}
+	for _, v := range testData {
+		t.Run(v.name, func(t *testing.T) {
+			actual, conflict := ThreeWay([]byte(v.base), []byte(v.current), []byte(v.generated))
+			assert.Equal(t, v.expect, string(actual))
+			assert.Equal(t, v.conflict, conflict)
+			assert.Equal(t, v.conflict, HasConflict(actual))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	lines := func(n int) []string {
+		ret := make([]string, n)
+		for i := range ret {
+			ret[i] = strconv.Itoa(r.Intn(4))
+		}
+		return ret
+	}
+	for i := 0; i < 500; i++ {
+		a, b := lines(r.Intn(30)), lines(r.Intn(30))
+		ret := match(a, b)
+
+		// the matched lines are equal and in order, and as many as the longest
+		// common subsequence.
+		var matched int
+		last := -1
+		for i, j := range ret {
+			if j < 0 {
+				continue
+			}
+			assert.Equal(t, a[i], b[j])
+			assert.Greater(t, j, last)
+			last = j
+			matched++
+		}
+		assert.Equal(t, lcs(a, b), matched, "%v %v", a, b)
+	}
+}
+
+func TestMatchLarge(t *testing.T) {
+	// a full n*m table of the lines would take 80GB.
+	a := make([]string, 100000)
+	for i := range a {
+		a[i] = strconv.Itoa(i) + "\n"
+	}
+	b := append(append(append([]string{}, a[:50000]...), "x\n"), a[50001:]...)
+	ret := match(a, b)
+	assert.Equal(t, -1, ret[50000])
+	assert.Equal(t, 99999, ret[99999])
+
+	base := strings.Join(a, "")
+	actual, conflict := ThreeWay([]byte(base), []byte(base+"y\n"), []byte(strings.Join(b, "")))
+	assert.False(t, conflict)
+	assert.Equal(t, strings.Join(b, "")+"y\n", string(actual))
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -21,35 +21,102 @@ var Cmd = &cobra.Command{
 }
 
 var gormCmd = &cobra.Command{
-	Use:   "gorm",
-	Short: "Generate gorm model",
-	PreRun: func(cmd *cobra.Command, args []string) {
-		// 如果指定了配置文件，从配置文件加载
-		if configFile != "" {
-			config, err := types.LoadConfig(configFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "加载配置文件失败: %v\n", err)
-				os.Exit(1)
-			}
-			// 保留命令行模式
-			mode := arg.Mode
-			arg = *config
-			arg.Mode = mode
-		}
-	},
+	Use:    "gorm",
+	Short:  "Generate gorm model",
+	PreRun: loadConfig,
 	Run: func(cmd *cobra.Command, args []string) {
 		arg.Mode = types.GORM
 		Run(arg)
 	},
 }
 
+var sqlxCmd = &cobra.Command{
+	Use:    "sqlx",
+	Short:  "Generate sqlx model",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		arg.Mode = types.SQLX
+		Run(arg)
+	},
+}
+
+var bunCmd = &cobra.Command{
+	Use:    "bun",
+	Short:  "Generate bun model",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		arg.Mode = types.BUN
+		Run(arg)
+	},
+}
+
+var sqlCmd = &cobra.Command{
+	Use:    "sql",
+	Short:  "Generate database/sql model",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		arg.Mode = types.SQL
+		Run(arg)
+	},
+}
+
+var xormCmd = &cobra.Command{
+	Use:    "xorm",
+	Short:  "Generate xorm model",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		arg.Mode = types.XORM
+		Run(arg)
+	},
+}
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the templates of the generators",
+}
+
+var templatesExportCmd = &cobra.Command{
+	Use:   "export [dir]",
+	Short: "Export the built-in templates to dir (default: templates) for customization",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "templates"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if err := ExportTemplates(dir); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// loadConfig 如果指定了配置文件，从配置文件加载
+func loadConfig(cmd *cobra.Command, args []string) {
+	if configFile == "" {
+		return
+	}
+	config, err := types.LoadConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "加载配置文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	// 保留仅用于命令行的参数
+	mode, dryRun, diff, check, adopt := arg.Mode, arg.DryRun, arg.Diff, arg.Check, arg.Adopt
+	arg = *config
+	arg.Mode, arg.DryRun, arg.Diff, arg.Check, arg.Adopt = mode, dryRun, diff, check, adopt
+}
+
 func init() {
 	// flags init
 	persistentFlags := Cmd.PersistentFlags()
 	persistentFlags.StringVarP(&configFile, "config", "c", "", "配置文件路径")
-	persistentFlags.StringVarP(&arg.DSN, "dsn", "d", "", "Mysql address")
+	persistentFlags.StringVarP(&arg.DSN, "dsn", "d", "", "Mysql or postgres address, or sqlite database file")
 	persistentFlags.StringSliceVarP(&arg.Table, "table", "t", []string{"*"}, "Patterns of table name")
 	persistentFlags.StringSliceVarP(&arg.Filename, "filename", "f", []string{"*.sql"}, "Patterns of SQL filename")
+	persistentFlags.StringVar(&arg.Migrations, "migrations", "", "The directory of the migration files which are replayed in order to build the schema")
+	persistentFlags.BoolVar(&arg.Tolerant, "tolerant", false, "Ignore the statements which are irrelevant to the schema in the SQL files with warnings, e.g. the output of mysqldump")
+	persistentFlags.StringVar(&arg.Dialect, "dialect", "", "SQL dialect of the SQL files and the database (mysql, postgres, sqlite)")
 	persistentFlags.StringVarP(&arg.Output, "output", "o", ".", "The adapter output directory")
 	persistentFlags.StringVarP(&arg.EntityOutput, "entity-output", "e", ".", "The entity output directory")
 	persistentFlags.StringVarP(&arg.RepoOutput, "repo-output", "i", ".", "The port output directory")
@@ -57,9 +124,22 @@ func init() {
 	persistentFlags.StringVarP(&arg.EntityPackage, "entity-package", "E", "", "The entity packge full name")
 	persistentFlags.BoolVarP(&arg.AutoAudit, "auto-audit", "a", false, "Whether to turn on automatic audit mode")
 	persistentFlags.StringSliceVar(&arg.MockTypes, "mock-type", nil, "Types of mock files to generate (sqlite, docker)")
+	persistentFlags.StringVar(&arg.Nullable, "nullable", "", "Go type strategy of the nullable columns (pointer, sql, generic)")
+	persistentFlags.BoolVar(&arg.DryRun, "dry-run", false, "List the files which would be created, overwritten, merged or skipped without writing them")
+	persistentFlags.BoolVar(&arg.Diff, "diff", false, "Print the unified diff between the existing files and the generated code without writing them")
+	persistentFlags.BoolVar(&arg.Check, "check", false, "Exit with an error if any generated file is out of date, the files are not written")
+	persistentFlags.BoolVar(&arg.Adopt, "adopt", false, "Overwrite the existing files which have no baseline with the generated code and save the baseline, review them with --diff first")
+	persistentFlags.StringVar(&arg.TemplatesDir, "templates-dir", "", "The directory of the custom templates which override the built-in templates with the same name")
+	persistentFlags.StringVar(&arg.Layout, "layout", "", "Layout of the generated files, split puts the generated code into _gen.go files")
 
 	// sub commands init
 	Cmd.AddCommand(gormCmd)
+	Cmd.AddCommand(sqlxCmd)
+	Cmd.AddCommand(bunCmd)
+	Cmd.AddCommand(sqlCmd)
+	Cmd.AddCommand(xormCmd)
+	templatesCmd.AddCommand(templatesExportCmd)
+	Cmd.AddCommand(templatesCmd)
 	Cmd.Version = buildVersion
 	Cmd.CompletionOptions.DisableDefaultCmd = true
 }
@@ -0,0 +1,140 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// Audit 是表中存在的审计列的列名, 未开启自动审计或表中不存在对应的列时为空
+type Audit struct {
+	// Creator 创建人, 创建时填充
+	Creator string
+	// Updater 更新人, 创建和更新时填充
+	Updater string
+	// CreatedTime 创建时间, 创建时填充
+	CreatedTime string
+	// UpdatedTime 更新时间, 创建和更新时填充
+	UpdatedTime string
+}
+
+// NewAudit 返回表中存在的审计列, 创建人和更新人的类型需要为 string, 创建时间和更新时间的类型需要为 time.Time
+func NewAudit(table *spec.Table, arg types.RunArg) (Audit, error) {
+	var a Audit
+	if !arg.AutoAudit {
+		return a, nil
+	}
+
+	config := arg.Audit.WithDefault()
+	for _, v := range []struct {
+		name   string
+		goType string
+		field  *string
+	}{
+		{config.Creator, "string", &a.Creator},
+		{config.Updater, "string", &a.Updater},
+		{config.CreatedTime, "time.Time", &a.CreatedTime},
+		{config.UpdatedTime, "time.Time", &a.UpdatedTime},
+	} {
+		c, ok := table.GetColumnByName(v.name)
+		if !ok {
+			continue
+		}
+		goType, err := c.GoType()
+		if err != nil {
+			return a, err
+		}
+		if goType != v.goType {
+			return a, fmt.Errorf("audit column %q of table %q must be %s, got %s", v.name, table.Name, v.goType, goType)
+		}
+		*v.field = c.Name
+	}
+	return a, nil
+}
+
+// IsValid 返回表中是否存在审计列
+func (a Audit) IsValid() bool {
+	return a != Audit{}
+}
+
+// HasOperator 返回表中是否存在创建人或更新人, 存在时需要从 context 中获取操作人
+func (a Audit) HasOperator() bool {
+	return a.Creator != "" || a.Updater != ""
+}
+
+// IsImmutable 返回更新时是否保持列的值不变, 即创建人和创建时间
+func (a Audit) IsImmutable(name string) bool {
+	return name != "" && (name == a.Creator || name == a.CreatedTime)
+}
+
+// CreateVariables 返回创建时填充审计列需要的变量: 操作人 operator 和当前时间 now
+func (a Audit) CreateVariables() string {
+	var list []string
+	if a.HasOperator() {
+		list = append(list, "operator := OperatorFromContext(ctx)")
+	}
+	if a.CreatedTime != "" || a.UpdatedTime != "" {
+		list = append(list, "now := time.Now()")
+	}
+	return strings.Join(list, "\n")
+}
+
+// OnCreate 返回创建时填充审计列的语句, v 是 PO 的变量名, 依赖 CreateVariables 声明的变量
+func (a Audit) OnCreate(v string) string {
+	var list []string
+	for _, c := range []struct{ name, value string }{
+		{a.Creator, "operator"},
+		{a.Updater, "operator"},
+		{a.CreatedTime, "now"},
+		{a.UpdatedTime, "now"},
+	} {
+		if c.name != "" {
+			list = append(list, fmt.Sprintf("%s.%s = %s", v, strcase.ToCamel(c.name), c.value))
+		}
+	}
+	return strings.Join(list, "\n")
+}
+
+// OnUpdate 返回更新时填充审计列的语句, v 是 PO 的变量名
+func (a Audit) OnUpdate(v string) string {
+	var list []string
+	if a.Updater != "" {
+		list = append(list, fmt.Sprintf("%s.%s = OperatorFromContext(ctx)", v, strcase.ToCamel(a.Updater)))
+	}
+	if a.UpdatedTime != "" {
+		list = append(list, fmt.Sprintf("%s.%s = time.Now()", v, strcase.ToCamel(a.UpdatedTime)))
+	}
+	return strings.Join(list, "\n")
+}
+
+// AuditTempData 是获取操作人的函数所在文件的模版数据
+type AuditTempData struct {
+	// AdapterPackageName 适配器的包名
+	AdapterPackageName string
+	// OperatorPackage 获取操作人的函数的完整包名, 为空时生成返回空字符串的默认实现
+	OperatorPackage string
+	// OperatorFunc 获取操作人的函数名
+	OperatorFunc string
+}
+
+// GenerateAudit 开启自动审计时生成获取操作人的函数 OperatorFromContext, 每个适配器包只生成一次
+func (l Layout) GenerateAudit(arg types.RunArg) error {
+	if !arg.AutoAudit {
+		return nil
+	}
+
+	data := AuditTempData{AdapterPackageName: PackageName(arg.Output)}
+	if fn := arg.Audit.OperatorFunc; fn != "" {
+		i := strings.LastIndex(fn, ".")
+		if i <= strings.LastIndex(fn, "/") || i == len(fn)-1 {
+			return fmt.Errorf("invalid operator_func %q, it should be like github.com/foo/bar.OperatorFromContext", fn)
+		}
+		data.OperatorPackage, data.OperatorFunc = fn[:i], fn[i+1:]
+	}
+
+	l.templates = TemplateFS
+	return l.GenerateFile(l.Filename(arg.Output, "audit"), "audit.go.tpl", data)
+}
@@ -0,0 +1,19 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    {{- if $.OperatorPackage}}
+
+    auditor "{{$.OperatorPackage}}"
+    {{- end}}
+)
+
+// OperatorFromContext returns the operator in ctx, it is used to fill the
+// creator and updater columns, replace it at initialization if necessary.
+{{- if $.OperatorPackage}}
+var OperatorFromContext func(ctx context.Context) string = auditor.{{$.OperatorFunc}}
+{{- else}}
+var OperatorFromContext = func(ctx context.Context) string {
+    return ""
+}
+{{- end}}
@@ -0,0 +1,36 @@
+package bun
+
+import (
+	"embed"
+	"text/template"
+
+	"github.com/xyzbit/codegen/sqlgen/gen"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// TemplateFS 是内置的模版, 可以通过 templates_dir 覆盖同名的模版
+//
+//go:embed *.tpl
+var TemplateFS embed.FS
+
+var funcMap = template.FuncMap{
+	// ClauseArgs 返回 where/having 子句的参数, in 和 not in 的参数需要使用 bun.In 包装
+	"ClauseArgs": func(clause *spec.Clause, pkg string) (string, error) {
+		return gen.WrapInClauseArgs(clause, pkg, "bun.In")
+	},
+}
+
+func Run(list []spec.Context, arg types.RunArg) error {
+	return gen.Run(list, arg, gen.Backend{
+		Name:      "bun",
+		Templates: TemplateFS,
+		FuncMaps:  []template.FuncMap{funcMap},
+		Helpers:   []string{"bun_tx", "bun_query"},
+		Query:     true,
+		Mock:      true,
+		Table: func(td gen.TempData) (gen.Table, error) {
+			return gen.Table{Methods: gen.QueryMethods}, nil
+		},
+	})
+}
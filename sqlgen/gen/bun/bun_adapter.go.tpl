@@ -0,0 +1,464 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    {{- if eq $.Dialect "sqlite"}}
+    "strings"
+    {{- end}}
+
+    {{if eq $.Dialect "mysql"}}"github.com/go-sql-driver/mysql"{{end}}
+    "github.com/samber/lo"
+    "github.com/uptrace/bun"
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
+
+    repo "{{$.RepoPackage}}"
+    entity "{{$.EntityPackage}}"
+)
+
+// {{UpperCamel $.Table.Name}}Adapter represents a {{$.Table.Name}} adapter.
+type {{UpperCamel $.Table.Name}}Adapter struct {
+    db *bun.DB
+}
+
+// New{{UpperCamel $.Table.Name}}Repo returns a new {{$.Table.Name}} adapter implemented {{$.Table.Name}}Repo.
+func New{{UpperCamel $.Table.Name}}Repo (
+    db *bun.DB,
+) repo.{{UpperCamel $.Table.Name}}Repo {
+    return &{{UpperCamel $.Table.Name}}Adapter{db: db}
+}
+
+// DB returns the transaction in ctx if it exists, otherwise returns the db.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DB(ctx context.Context) bun.IDB {
+    if tx, ok := BunTxFromContext(ctx); ok {
+        return tx
+    }
+    return m.db
+}
+
+// Create creates  {{$.Table.Name}} data.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*entity.{{UpperCamel $.Table.Name}}) error {
+    if len(es)==0{
+        return fmt.Errorf("data is empty")
+    }
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.CreateVariables}}
+    {{- end}}
+
+    pos := lo.Map(es, func(v *entity.{{UpperCamel $.Table.Name}}, _ int) *{{UpperCamel $.Table.Name}} {
+        {{- if $.Audit.IsValid}}
+        p := to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{$.Audit.OnCreate "p"}}
+        return p
+        {{- else}}
+        return to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{- end}}
+    })
+
+    _, err := m.DB(ctx).NewInsert().Model(&pos).Exec(ctx)
+    return err
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// GetByKey get {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    err := m.DB(ctx).NewSelect().
+        Model(&po).
+        {{- range $.Table.PrimaryColumnList}}
+        Where("? = ?", bun.Ident("{{.Name}}"), key.{{UpperCamel .Name}}).
+        {{- end}}
+        Limit(1).
+        Scan(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- else -}}
+// GetByID get {{$.Table.Name}} by id.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    err := m.DB(ctx).NewSelect().
+        Model(&po).
+        Where("? = ?", bun.Ident("{{$.Table.PrimaryColumn.Name}}"), id).
+        Limit(1).
+        Scan(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- end}}
+
+// List list {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) List(ctx context.Context, query *repo.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
+    var pos []*{{UpperCamel $.Table.Name}}
+
+    err := BunQuery(m.DB(ctx).NewSelect().Model(&pos), query).Scan(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $.Table.Name}}, _ int) *entity.{{UpperCamel $.Table.Name}} {
+        return to{{UpperCamel $.Table.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+}
+
+// Count count {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Count(ctx context.Context, query *repo.Query) (int64, error) {
+    count, err := BunQuery(m.DB(ctx).NewSelect().Model((*{{UpperCamel $.Table.Name}})(nil)), query).Count(ctx)
+
+    return int64(count), err
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// UpdateByKey update {{$.Table.Name}} by primary key{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) UpdateByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- range $.Table.PrimaryColumnList}}
+    p.{{UpperCamel .Name}} = key.{{UpperCamel .Name}}
+    {{- end}}
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+    {{- if $.Version.IsValid}}
+
+    result, err := m.DB(ctx).NewUpdate().Model(p).OmitZero().WherePK().
+        ExcludeColumn("{{$.Version.Column}}").
+        Set("{{$.Version.Column}} = {{$.Version.Column}} + 1").
+        Where("{{$.Version.Column}} = ?", p.{{$.Version.Field}}).
+        Exec(ctx)
+    if err != nil {
+        return err
+    }
+    {{- template "version" $}}
+    {{- else}}
+
+    _, err := m.DB(ctx).NewUpdate().Model(p).OmitZero().WherePK().Exec(ctx)
+    return err
+    {{- end}}
+}
+{{- else -}}
+// Update update {{$.Table.Name}}{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+    {{- if $.Version.IsValid}}
+
+    result, err := m.DB(ctx).NewUpdate().Model(p).OmitZero().WherePK().
+        ExcludeColumn("{{$.Version.Column}}").
+        Set("{{$.Version.Column}} = {{$.Version.Column}} + 1").
+        Where("{{$.Version.Column}} = ?", p.{{$.Version.Field}}).
+        Exec(ctx)
+    if err != nil {
+        return err
+    }
+    {{- template "version" $}}
+    {{- else}}
+
+    _, err := m.DB(ctx).NewUpdate().Model(p).OmitZero().WherePK().Exec(ctx)
+    return err
+    {{- end}}
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// DeleteByKey delete {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    _, err := m.DB(ctx).NewDelete().
+        Model((*{{UpperCamel $.Table.Name}})(nil)).
+        {{- range $.Table.PrimaryColumnList}}
+        Where("? = ?", bun.Ident("{{.Name}}"), key.{{UpperCamel .Name}}).
+        {{- end}}
+        Exec(ctx)
+    return err
+}
+{{- else -}}
+// Delete delete {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id {{PrimaryKeyType}}) error {
+    _, err := m.DB(ctx).NewDelete().
+        Model((*{{UpperCamel $.Table.Name}})(nil)).
+        Where("? = ?", bun.Ident("{{$.Table.PrimaryColumn.Name}}"), id).
+        Exec(ctx)
+    return err
+}
+{{- end}}
+
+// IsDuplicatedKeyError use to check error is unique key conflict error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
+    {{- if eq $.Dialect "postgres"}}
+    // the errors of github.com/lib/pq and github.com/jackc/pgx both implement SQLState.
+    var pgErr interface{ SQLState() string }
+    return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+    {{- else if eq $.Dialect "sqlite"}}
+    // github.com/mattn/go-sqlite3 and modernc.org/sqlite report the same message.
+    return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+    {{- else}}
+    var mysqlErr *mysql.MySQLError
+    return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+    {{- end}}
+}
+
+// IsNotFoundError use to check error is record not found error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
+    return errors.Is(err, sql.ErrNoRows)
+}
+
+{{- range $stmt := $.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{- range $tx := $.Transaction}}
+// {{UpperCamel $tx.FuncName}} is generated from sql:
+// {{LineComment $tx.SQL}}
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg repo.{{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*repo.{{$tx.ResultStructureName}}, {{end}}error) {
+    {{- if $tx.HasResult}}
+    var result repo.{{$tx.ResultStructureName}}
+    {{- end}}
+    // join the outer transaction if it exists.
+    err := BunTransaction(ctx, m.db, func(txCtx context.Context) error {
+        var err error
+        {{- range $v := $tx.Statements}}
+        {{- if IsInsert $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data...); err != nil {
+            return err
+        }
+        {{- else if IsSelect $v}}
+        if result.{{UpperCamel $v.FuncName}}, err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Having.IsValid}}, arg.{{UpperCamel $v.FuncName}}Having{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsUpdate $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsDelete $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- end}}
+        {{- end}}
+        return nil
+    })
+    {{- if $tx.HasResult}}
+    if err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    return err
+    {{- end}}
+}
+{{range $stmt := $tx.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{end}}
+
+// {{UpperCamel $.Table.Name}} represents a {{$.Table.Name}} struct data.
+type {{UpperCamel $.Table.Name}} struct {
+    bun.BaseModel `bun:"table:{{$.Table.Name}}"`
+    {{range $.Table.Columns}}
+    {{UpperCamel .Name}} {{.POGoType}} `bun:"{{.Name}}{{if IsPrimary .Name}},pk{{end}}{{if .AutoIncrement}},autoincrement{{end}}" json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+}
+
+func to{{UpperCamel $.Table.Name}}PO(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) *{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToPO "e"}},
+        {{- end}}
+    }
+}
+
+func to{{UpperCamel $.Table.Name}}Entity(ctx context.Context, po *{{UpperCamel $.Table.Name}}) *entity.{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &entity.{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToEntity "po"}},
+        {{- end}}
+    }
+}
+
+{{define "insert"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $stmt.TableInfo.Name}}) error {
+    if len(data) == 0 {
+        return fmt.Errorf("data is empty")
+    }
+
+    pos := lo.Map(data, func(v *entity.{{UpperCamel $stmt.TableInfo.Name}}, _ int) *{{UpperCamel $stmt.TableInfo.Name}} {
+        return to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, v)
+    })
+
+    _, err := m.DB(ctx).NewInsert().
+        Model(&pos).
+        Column({{- range $i, $c := $stmt.ColumnInfo}}{{if $i}}, {{end}}"{{$c.Name}}"{{end -}}).
+        Exec(ctx)
+    return err
+}
+
+{{end}}
+{{define "select"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having repo.{{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if IsExtraResult $stmt.ReceiverName}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error) {
+    {{- $model := "&pos"}}
+    {{- if IsExtraResult $stmt.ReceiverName}}
+    {{- $model = "&result"}}
+    {{- if $stmt.Limit.One}}
+    var result repo.{{$stmt.ReceiverName}}
+    {{- else}}
+    var result []*repo.{{$stmt.ReceiverName}}
+    {{- end}}
+    {{- else}}
+    {{- if $stmt.Limit.One}}
+    {{- $model = "&po"}}
+    var po {{UpperCamel $stmt.FromInfo.Name}}
+    {{- else}}
+    var pos []*{{UpperCamel $stmt.FromInfo.Name}}
+    {{- end}}
+    {{- end}}
+    q := m.DB(ctx).NewSelect().
+        Model({{$model}}).
+        ColumnExpr(`{{$stmt.SelectSQL}}`)
+    {{- if $stmt.Distinct}}
+    q = q.Distinct()
+    {{- end}}
+    {{- if $stmt.Where.IsValid}}
+    q = q.Where({{$stmt.Where.SQL}}, {{ClauseArgs $stmt.Where "where"}})
+    {{- end}}
+    {{- if $stmt.GroupBy.IsValid}}
+    q = q.GroupExpr({{$stmt.GroupBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Having.IsValid}}
+    q = q.Having({{$stmt.Having.SQL}}, {{ClauseArgs $stmt.Having "having"}})
+    {{- end}}
+    {{- if $stmt.OrderBy.IsValid}}
+    q = q.OrderExpr({{$stmt.OrderBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Limit.Multiple}}
+    q = q.Limit({{$stmt.Limit.LimitParameter "limit"}})
+    {{- if $stmt.Limit.Offset}}
+    q = q.Offset({{$stmt.Limit.OffsetParameter "limit"}})
+    {{- end}}
+    {{- else if $stmt.Limit.IsValid}}
+    q = q.Limit({{$stmt.Limit.Count}})
+    {{- if $stmt.Limit.Offset}}
+    q = q.Offset({{$stmt.Limit.Offset}})
+    {{- end}}
+    {{- end}}
+    if err := q.Scan(ctx); err != nil {
+        return nil, err
+    }
+    {{- if IsExtraResult $stmt.ReceiverName}}
+
+    return {{if $stmt.Limit.One}}&{{end}}result, nil
+    {{- else if $stmt.Limit.One}}
+
+    return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po), nil
+    {{- else}}
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $stmt.FromInfo.Name}}, _ int) *entity.{{UpperCamel $stmt.FromInfo.Name}} {
+        return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+    {{- end}}
+}
+
+{{end}}
+{{define "update"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data *entity.{{UpperCamel $stmt.TableInfo.Name}}{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    q := m.DB(ctx).NewUpdate().
+        Model(to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, data)).
+        Column({{- range $i, $c := $stmt.ColumnInfo}}{{if $i}}, {{end}}"{{$c.Name}}"{{end -}})
+    {{- template "condition" $stmt}}
+
+    _, err := q.Exec(ctx)
+    return err
+}
+
+{{end}}
+{{define "delete"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    q := m.DB(ctx).NewDelete().
+        Model((*{{UpperCamel $stmt.FromInfo.Name}})(nil))
+    {{- template "condition" $stmt}}
+
+    _, err := q.Exec(ctx)
+    return err
+}
+
+{{end}}
+{{define "condition"}}
+{{- $stmt := .}}
+    {{- if $stmt.Where.IsValid}}
+    q = q.Where({{$stmt.Where.SQL}}, {{ClauseArgs $stmt.Where "where"}})
+    {{- else}}
+    // bun requires at least one where condition for update and delete.
+    q = q.Where("1 = 1")
+    {{- end}}
+    {{- if $stmt.OrderBy.IsValid}}
+    q = q.OrderExpr({{$stmt.OrderBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Limit.Multiple}}
+    q = q.Limit({{$stmt.Limit.LimitParameter "limit"}})
+    {{- else if $stmt.Limit.One}}
+    q = q.Limit(1)
+    {{- end}}
+{{- end}}
+{{define "versionComment"}}
+{{- if $.Version.IsValid}}, {{$.Version.Column}} is increased and repo.ErrVersionConflict is returned if it is out of date{{end}}
+{{- end}}
+{{define "version"}}
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}}++
+
+    return nil
+{{- end}}
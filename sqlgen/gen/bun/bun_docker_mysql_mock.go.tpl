@@ -0,0 +1,107 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    _ "github.com/go-sql-driver/mysql"
+    "github.com/testcontainers/testcontainers-go"
+    "github.com/testcontainers/testcontainers-go/wait"
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect/mysqldialect"
+
+    repo "{{$.RepoPackage}}"
+)
+
+// DockerMock{{UpperCamel $.Table.Name}}Adapter Docker MySQL 测试适配器
+type DockerMock{{UpperCamel $.Table.Name}}Adapter struct {
+    // 内嵌真实适配器, 复用内置方法和 sql 注释生成的查询方法
+    *{{UpperCamel $.Table.Name}}Adapter
+    db        *bun.DB
+    container testcontainers.Container
+}
+
+// NewDockerMock{{UpperCamel $.Table.Name}}Repo 创建一个新的基于 Docker MySQL 的测试适配器
+func NewDockerMock{{UpperCamel $.Table.Name}}Repo() (repo.{{UpperCamel $.Table.Name}}Repo, error) {
+    ctx := context.Background()
+    req := testcontainers.ContainerRequest{
+        Image:        "mysql:8.0",
+        ExposedPorts: []string{"3306/tcp"},
+        Env: map[string]string{
+            "MYSQL_ROOT_PASSWORD": "test",
+            "MYSQL_DATABASE":      "test",
+        },
+        WaitingFor: wait.ForAll(
+            wait.ForLog("ready for connections"),
+            wait.ForListeningPort("3306/tcp"),
+        ),
+    }
+
+    container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+        ContainerRequest: req,
+        Started:          true,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to start container: %w", err)
+    }
+
+    // 获取映射端口
+    mappedPort, err := container.MappedPort(ctx, "3306")
+    if err != nil {
+        container.Terminate(ctx)
+        return nil, fmt.Errorf("failed to get container port: %w", err)
+    }
+
+    // 使用 localhost 而不是容器 IP
+    dsn := fmt.Sprintf("root:test@tcp(localhost:%s)/test?charset=utf8mb4&parseTime=True&loc=Local",
+        mappedPort.Port(),
+    )
+
+    // 添加重试逻辑
+    var sqldb *sql.DB
+    maxRetries := 5
+    var lastErr error
+    for i := 0; i < maxRetries; i++ {
+        sqldb, lastErr = sql.Open("mysql", dsn)
+        if lastErr == nil {
+            lastErr = sqldb.PingContext(ctx)
+        }
+        if lastErr == nil {
+            break
+        }
+        fmt.Printf("retry %d: %v\n", i+1, lastErr)
+        time.Sleep(time.Second * 2)
+    }
+    if lastErr != nil {
+        _ = container.Terminate(ctx)
+        return nil, fmt.Errorf("failed to connect to database after %d retries: %w", maxRetries, lastErr)
+    }
+    db := bun.NewDB(sqldb, mysqldialect.New())
+
+    // 根据 PO 结构创建表
+    if _, err := db.NewCreateTable().Model((*{{UpperCamel $.Table.Name}})(nil)).IfNotExists().Exec(ctx); err != nil {
+        _ = container.Terminate(ctx)
+        return nil, fmt.Errorf("failed to create table: %w", err)
+    }
+
+    return &DockerMock{{UpperCamel $.Table.Name}}Adapter{
+        {{UpperCamel $.Table.Name}}Adapter: &{{UpperCamel $.Table.Name}}Adapter{db: db},
+        db:        db,
+        container: container,
+    }, nil
+}
+
+func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) Close() error {
+    if m.container != nil {
+        if err := m.container.Terminate(context.Background()); err != nil {
+            return fmt.Errorf("failed to terminate container: %w", err)
+        }
+    }
+
+    if m.db != nil {
+        return m.db.Close()
+    }
+    return nil
+}
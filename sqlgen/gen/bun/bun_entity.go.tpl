@@ -0,0 +1,20 @@
+package entity
+{{with $.Table.TypeImports}}
+import (
+    {{- range .}}
+    "{{.}}"
+    {{- end}}
+)
+{{end}}
+// {{UpperCamel $.Table.Name}} column names.
+const(
+    {{range $.Table.Columns}}
+    {{UpperCamel $.Table.Name}}{{UpperCamel .Name}} = "{{.Name}}" {{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+)
+
+// {{UpperCamel $.Table.Name}} entity a {{$.Table.Name}} struct data.
+type {{UpperCamel $.Table.Name}} struct {
+    {{- range $.Table.Columns}}
+    {{UpperCamel .Name}} {{.GoType}} `json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}
+    {{- end}}
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "github.com/uptrace/bun"
+
+    repo "{{$.RepoPackage}}"
+)
+
+// BunQuery applies the clauses of query to the select query q, the columns are
+// quoted by bun.Ident, a nil query matches all rows.
+func BunQuery(q *bun.SelectQuery, query *repo.Query) *bun.SelectQuery {
+    clauses := query.Clauses()
+    for _, v := range clauses.Where {
+        where := q.Where
+        if v.Or {
+            where = q.WhereOr
+        }
+        switch v.Operator {
+        case "IN", "NOT IN":
+            q = where("? "+v.Operator+" (?)", bun.Ident(v.Column), bun.In(v.Args[0]))
+        case "BETWEEN":
+            q = where("? BETWEEN ? AND ?", bun.Ident(v.Column), v.Args[0], v.Args[1])
+        case "IS NULL", "IS NOT NULL":
+            q = where("? "+v.Operator, bun.Ident(v.Column))
+        default:
+            if v.Column == "" {
+                q = where(v.Operator, v.Args...)
+            } else {
+                q = where("? "+v.Operator+" ?", bun.Ident(v.Column), v.Args[0])
+            }
+        }
+    }
+    if len(clauses.Columns) > 0 {
+        q = q.Column(clauses.Columns...)
+    }
+    if len(clauses.Group) > 0 {
+        q = q.Group(clauses.Group...)
+    }
+    for _, v := range clauses.Order {
+        switch {
+        case v.Column == "":
+            q = q.OrderExpr(v.Expr)
+        case v.Desc:
+            q = q.OrderExpr("? DESC", bun.Ident(v.Column))
+        default:
+            q = q.OrderExpr("? ASC", bun.Ident(v.Column))
+        }
+    }
+    if clauses.Limit > 0 {
+        q = q.Limit(clauses.Limit)
+    }
+    if clauses.Offset > 0 {
+        q = q.Offset(clauses.Offset)
+    }
+    return q
+}
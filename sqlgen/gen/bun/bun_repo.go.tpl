@@ -0,0 +1,117 @@
+package {{$.RepoPackageName}}
+
+import (
+    "context"
+
+    "github.com/uptrace/bun"
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
+
+    entity "{{$.EntityPackage}}"
+)
+
+type {{UpperCamel $.Table.Name}}Repo interface {
+{{- if $.Split}}
+    {{UpperCamel $.Table.Name}}RepoExtension
+{{end}}
+    DB(ctx context.Context) bun.IDB
+
+    {{if $.Table.HasCompositePrimaryKey -}}
+    GetByKey(ctx context.Context, key {{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error)
+    {{- else -}}
+    GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error)
+    {{- end}}
+    Create(ctx context.Context, data ...*entity.{{UpperCamel $.Table.Name}}) error
+    List(ctx context.Context, query *Query) ([]*entity.{{UpperCamel $.Table.Name}}, error)
+    Count(ctx context.Context, query *Query) (int64, error)
+    {{if $.Table.HasCompositePrimaryKey -}}
+    UpdateByKey(ctx context.Context, key {{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error
+    {{- else -}}
+    Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error
+    {{- end}}
+    {{if $.Table.HasCompositePrimaryKey -}}
+    DeleteByKey(ctx context.Context, key {{$.Table.KeyStructureName}}) error
+    {{- else -}}
+    Delete(ctx context.Context, id {{PrimaryKeyType}}) error
+    {{- end}}
+
+    IsDuplicatedKeyError(err error) bool
+    IsNotFoundError(err error) bool
+{{- range $stmt := $.InsertStmt}}
+
+    // {{UpperCamel $stmt.FuncName}} is generated from sql:
+    // {{LineComment $stmt.SQL}}
+    {{UpperCamel $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $.Table.Name}}) error
+{{- end}}
+{{- range $stmt := $.SelectStmt}}
+
+    // {{UpperCamel $stmt.FuncName}} is generated from sql:
+    // {{LineComment $stmt.SQL}}
+    {{UpperCamel $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where {{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having {{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit {{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if IsExtraResult $stmt.ReceiverName}}{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error)
+{{- end}}
+{{- range $stmt := $.UpdateStmt}}
+
+    // {{UpperCamel $stmt.FuncName}} is generated from sql:
+    // {{LineComment $stmt.SQL}}
+    {{UpperCamel $stmt.FuncName}}(ctx context.Context, data *entity.{{UpperCamel $.Table.Name}}{{if $stmt.Where.IsValid}}, where {{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit {{$stmt.Limit.ParameterStructureName}}{{end}}) error
+{{- end}}
+{{- range $stmt := $.DeleteStmt}}
+
+    // {{UpperCamel $stmt.FuncName}} is generated from sql:
+    // {{LineComment $stmt.SQL}}
+    {{UpperCamel $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where {{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit {{$stmt.Limit.ParameterStructureName}}{{end}}) error
+{{- end}}
+{{- range $tx := $.Transaction}}
+
+    // {{UpperCamel $tx.FuncName}} is generated from sql:
+    // {{LineComment $tx.SQL}}
+    {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg {{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*{{$tx.ResultStructureName}}, {{end}}error)
+{{- end}}
+}
+{{- if $.Table.HasCompositePrimaryKey}}
+
+{{$.Table.KeyStructure}}
+{{- end}}
+{{template "structures" $.Context}}
+{{- range $tx := $.Transaction}}
+{{template "structures" $tx.Context}}
+{{- if $tx.HasParameter}}
+{{$tx.ParameterStructure "entity"}}
+{{end}}
+{{- if $tx.HasResult}}
+{{$tx.ResultStructure "entity"}}
+{{end}}
+{{- end}}
+
+{{define "structures"}}
+{{- $ctx := .}}
+{{range $stmt := $ctx.SelectStmt}}
+{{- if $stmt.Where.IsValid}}
+{{$stmt.Where.ParameterStructure "Where"}}
+{{end}}
+{{- if $stmt.Having.IsValid}}
+{{$stmt.Having.ParameterStructure "Having"}}
+{{end}}
+{{- if $stmt.Limit.Multiple}}
+{{$stmt.Limit.ParameterStructure}}
+{{end}}
+{{- $stmt.ReceiverStructure "bun"}}
+{{end}}
+{{- range $stmt := $ctx.UpdateStmt}}
+{{- if $stmt.Where.IsValid}}
+{{$stmt.Where.ParameterStructure "Where"}}
+{{end}}
+{{- if $stmt.Limit.Multiple}}
+{{$stmt.Limit.ParameterStructure}}
+{{end}}
+{{- end}}
+{{- range $stmt := $ctx.DeleteStmt}}
+{{- if $stmt.Where.IsValid}}
+{{$stmt.Where.ParameterStructure "Where"}}
+{{end}}
+{{- if $stmt.Limit.Multiple}}
+{{$stmt.Limit.ParameterStructure}}
+{{end}}
+{{- end}}
+{{end}}
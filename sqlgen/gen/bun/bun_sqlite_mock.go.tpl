@@ -0,0 +1,65 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "strings"
+
+    "github.com/uptrace/bun"
+    "github.com/uptrace/bun/dialect/sqlitedialect"
+    "github.com/uptrace/bun/driver/sqliteshim"
+
+    repo "{{$.RepoPackage}}"
+)
+
+// SQLiteMock{{UpperCamel $.Table.Name}}Adapter SQLite 测试适配器
+type SQLiteMock{{UpperCamel $.Table.Name}}Adapter struct {
+    // 内嵌真实适配器, 复用内置方法和 sql 注释生成的查询方法
+    *{{UpperCamel $.Table.Name}}Adapter
+    db *bun.DB
+}
+
+// NewSQLiteMock{{UpperCamel $.Table.Name}}Repo 创建一个新的基于 SQLite 的测试适配器
+func NewSQLiteMock{{UpperCamel $.Table.Name}}Repo() (repo.{{UpperCamel $.Table.Name}}Repo, error) {
+    // 使用内存数据库
+    sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+    if err != nil {
+        return nil, fmt.Errorf("failed to connect database: %w", err)
+    }
+    db := bun.NewDB(sqldb, sqlitedialect.New())
+
+    // 启用外键约束
+    if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+        return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+    }
+
+    // 根据 PO 结构创建表
+    if _, err := db.NewCreateTable().Model((*{{UpperCamel $.Table.Name}})(nil)).IfNotExists().Exec(context.Background()); err != nil {
+        return nil, fmt.Errorf("failed to create table: %w", err)
+    }
+
+    return &SQLiteMock{{UpperCamel $.Table.Name}}Adapter{
+        {{UpperCamel $.Table.Name}}Adapter: &{{UpperCamel $.Table.Name}}Adapter{db: db},
+        db: db,
+    }, nil
+}
+
+func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
+    if err == nil {
+        return false
+    }
+    return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Reset(ctx context.Context) error {
+    _, err := m.db.NewDelete().Model((*{{UpperCamel $.Table.Name}})(nil)).Where("1 = 1").Exec(ctx)
+    return err
+}
+
+func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Close() error {
+    if m.db != nil {
+        return m.db.Close()
+    }
+    return nil
+}
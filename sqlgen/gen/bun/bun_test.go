@@ -0,0 +1,191 @@
+package bun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/gen/gentest"
+	"github.com/xyzbit/codegen/sqlgen/gen/testdata"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// require 是生成的代码依赖的模块
+var require = []string{
+	"github.com/go-sql-driver/mysql v1.8.1",
+	"github.com/samber/lo v1.49.1",
+	"github.com/uptrace/bun v1.2.16",
+}
+
+// sqliteRequire 是使用 sqlite 内存数据库运行生成的代码时依赖的模块
+var sqliteRequire = append(require,
+	"github.com/mattn/go-sqlite3 v1.14.22",
+	"github.com/uptrace/bun/dialect/sqlitedialect v1.2.16",
+)
+
+// findOneTest 使用 sqlite 内存数据库运行带 having 条件的查询, mysql 方言生成的查询 sqlite 同样支持
+const findOneTest = `package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestFindOne(t *testing.T) {
+	ctx := context.Background()
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE foo (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NULL UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewFooRepo(db)
+	if err := r.Create(ctx, &entity.Foo{Name: "foo"}, &entity.Foo{Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 1, Valid: true}})
+	if err != nil || result.C.Int64 != 2 {
+		t.Fatalf("FindOne: %v, %v", result, err)
+	}
+	if _, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 2, Valid: true}}); !r.IsNotFoundError(err) {
+		t.Fatalf("FindOne having: %v", err)
+	}
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, testdata.TestSql), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/find_one_test.go", findOneTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestRunBuild(t *testing.T) {
+	for name, layout := range map[string]string{"default": "", "split": types.LayoutSplit} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			arg := gentest.RunArg(dir)
+			arg.Layout = layout
+			arg.AutoAudit = true
+			err := Run(gentest.Context(t, testdata.BuildSql), arg)
+			assert.NoError(t, err)
+			gentest.Vet(t, dir, require...)
+		})
+	}
+}
+
+// sqliteTest 使用 sqlite 内存数据库运行生成的适配器
+const sqliteTest = `package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+type page struct{}
+
+func (page) GetOrderBy() string { return ` + "`" + `{"id":"descend"}` + "`" + ` }
+func (page) GetPage() uint64 { return 1 }
+func (page) GetPageSize() uint64 { return 2 }
+
+func TestOrder(t *testing.T) {
+	ctx := context.Background()
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	defer db.Close()
+	if _, err := db.NewCreateTable().Model((*Order)(nil)).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOrderRepo(db)
+	if err := r.Create(ctx, &entity.Order{Email: "foo@example.com", Name: "foo"}, &entity.Order{Email: "bar@example.com", Name: "bar"}, &entity.Order{Email: "baz@example.com", Name: "baz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := r.List(ctx, service.NewQuery().Like("email", "ba").OrderBy("id"))
+	if err != nil || len(list) != 2 || list[0].Name != "bar" || list[1].Name != "baz" {
+		t.Fatalf("List: %v, %v", list, err)
+	}
+	list, err = r.List(ctx, service.NewQuery().In("id", []int64{1, 3}).Not("name", "baz"))
+	if err != nil || len(list) != 1 || list[0].Name != "foo" {
+		t.Fatalf("List in: %v, %v", list, err)
+	}
+	list, err = r.List(ctx, service.NewQuery().Page(page{}))
+	if err != nil || len(list) != 2 || list[0].Id != 3 {
+		t.Fatalf("List page: %v, %v", list, err)
+	}
+	count, err := r.Count(ctx, service.NewQuery().Gt("id", 1).Limit(1))
+	if err != nil || count != 2 {
+		t.Fatalf("Count: %d, %v", count, err)
+	}
+	count, err = r.Count(ctx, nil)
+	if err != nil || count != 3 {
+		t.Fatalf("Count all: %d, %v", count, err)
+	}
+
+	// DB 与 List/Count 一样加入 ctx 中的事务
+	err = BunTransaction(ctx, db, func(txCtx context.Context) error {
+		if _, err := r.DB(txCtx).NewDelete().Model((*Order)(nil)).Where("id = ?", 1).Exec(txCtx); err != nil {
+			return err
+		}
+		count, err := r.Count(txCtx, nil)
+		if err != nil || count != 2 {
+			t.Fatalf("Count in tx: %d, %v", count, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 1); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID deleted: %v", err)
+	}
+}
+`
+
+func TestRunSQLite(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Dialect = types.DialectSQLite
+	err := Run(gentest.DialectContext(t, types.DialectSQLite, testdata.SQLiteSql), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/sqlite_test.go", sqliteTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestRunConflictFuncName(t *testing.T) {
+	for _, fn := range []string{"GetByID", "List"} {
+		dir := t.TempDir()
+		err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: `+fn+`
+select * from foo where id = ? limit 1;`), gentest.RunArg(dir))
+		assert.Error(t, err, fn)
+	}
+}
@@ -0,0 +1,34 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+
+    "github.com/uptrace/bun"
+)
+
+type bunTxKey struct{}
+
+// NewBunTxContext returns a new context carrying the bun transaction,
+// the adapters called with the context run in the transaction.
+func NewBunTxContext(ctx context.Context, tx bun.Tx) context.Context {
+    return context.WithValue(ctx, bunTxKey{}, tx)
+}
+
+// BunTxFromContext returns the bun transaction in ctx, ok is false if not exists.
+func BunTxFromContext(ctx context.Context) (tx bun.Tx, ok bool) {
+    tx, ok = ctx.Value(bunTxKey{}).(bun.Tx)
+    return tx, ok
+}
+
+// BunTransaction runs fn in a transaction, it joins the outer transaction if
+// ctx already carries one, otherwise it begins a new transaction and commits
+// it if fn returns nil.
+func BunTransaction(ctx context.Context, db *bun.DB, fn func(txCtx context.Context) error) error {
+    if _, ok := BunTxFromContext(ctx); ok {
+        return fn(ctx)
+    }
+
+    return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+        return fn(NewBunTxContext(ctx, tx))
+    })
+}
@@ -0,0 +1,170 @@
+// Package gen contains the helpers shared by the code generators of
+// different orm backends.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/xyzbit/codegen/pkg/merge"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// FuncMap 是各个后端模版共用的函数
+var FuncMap = template.FuncMap{
+	"IsInsert": func(dml spec.DML) bool {
+		_, ok := dml.(*spec.InsertStmt)
+		return ok
+	},
+	"IsSelect": func(dml spec.DML) bool {
+		_, ok := dml.(*spec.SelectStmt)
+		return ok
+	},
+	"IsUpdate": func(dml spec.DML) bool {
+		_, ok := dml.(*spec.UpdateStmt)
+		return ok
+	},
+	"IsDelete": func(dml spec.DML) bool {
+		_, ok := dml.(*spec.DeleteStmt)
+		return ok
+	},
+}
+
+// TableFuncMap 返回与表相关的模版函数
+func TableFuncMap(ctx spec.Context) template.FuncMap {
+	return template.FuncMap{
+		"IsPrimary": func(name string) bool {
+			return ctx.Table.IsPrimary(name)
+		},
+		"IsExtraResult": func(name string) bool {
+			return name != strcase.ToCamel(ctx.Table.Name)
+		},
+		"MethodName": MethodName(ctx),
+		"PrimaryKeyType": func() (string, error) {
+			return ctx.Table.PrimaryColumn().GoType()
+		},
+	}
+}
+
+// PackageName 返回输出目录对应的包名
+func PackageName(output string) string {
+	tmps := strings.Split(filepath.ToSlash(output), "/")
+	return tmps[len(tmps)-1]
+}
+
+// CheckFuncName 检查 sql 注释中的函数名是否与内置方法冲突
+func CheckFuncName(ctx spec.Context, builtinMethods map[string]struct{}) error {
+	var funcNames []string
+	for _, v := range ctx.InsertStmt {
+		funcNames = append(funcNames, v.FuncName)
+	}
+	for _, v := range ctx.SelectStmt {
+		funcNames = append(funcNames, v.FuncName)
+	}
+	for _, v := range ctx.UpdateStmt {
+		funcNames = append(funcNames, v.FuncName)
+	}
+	for _, v := range ctx.DeleteStmt {
+		funcNames = append(funcNames, v.FuncName)
+	}
+	for _, v := range ctx.Transaction {
+		funcNames = append(funcNames, v.FuncName)
+	}
+	for _, fn := range funcNames {
+		if _, ok := builtinMethods[strcase.ToCamel(fn)]; ok {
+			return fmt.Errorf("function %q of table %q conflicts with built-in method", fn, ctx.Table.Name)
+		}
+	}
+	return nil
+}
+
+// MethodName 返回 sql 注释中函数名对应的适配器方法名,
+// 事务内的语句只在事务方法中调用, 因此生成为私有方法.
+func MethodName(ctx spec.Context) func(fn string) string {
+	inner := map[string]struct{}{}
+	for _, tx := range ctx.Transaction {
+		for _, v := range tx.Statements {
+			switch stmt := v.(type) {
+			case *spec.InsertStmt:
+				inner[stmt.FuncName] = struct{}{}
+			case *spec.SelectStmt:
+				inner[stmt.FuncName] = struct{}{}
+			case *spec.UpdateStmt:
+				inner[stmt.FuncName] = struct{}{}
+			case *spec.DeleteStmt:
+				inner[stmt.FuncName] = struct{}{}
+			}
+		}
+	}
+	return func(fn string) string {
+		if _, ok := inner[fn]; ok {
+			return strcase.ToLowerCamel(fn)
+		}
+		return strcase.ToCamel(fn)
+	}
+}
+
+// baselineDir 是保存基线的目录, 位于生成文件的同级目录, 需要与代码一起提交.
+const baselineDir = ".codegen"
+
+func baselineFilename(filename string) string {
+	return filepath.Join(filepath.Dir(filename), baselineDir, filepath.Base(filename))
+}
+
+// mergeFile 计算生成的内容与已存在的文件合并后的结果, adopt 为 true 时覆盖没有基线的文件
+func mergeFile(filename string, generated []byte, adopt bool) (*fileChange, error) {
+	c := &fileChange{filename: filename, content: generated, baseline: generated}
+	current, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		c.action = actionCreate
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.current = current
+
+	if merge.HasConflict(current) {
+		return c.skip("has unresolved conflicts"), nil
+	}
+	base, err := os.ReadFile(baselineFilename(filename))
+	if os.IsNotExist(err) {
+		// 没有基线时 (如升级前生成的文件) 无法区分生成的代码和手写的代码, 不做合并:
+		// 与生成的内容一致时只补充基线, 否则交给用户, 使用 adopt 时用生成的内容覆盖
+		switch {
+		case bytes.Equal(current, generated):
+			c.action = actionUnchanged
+		case adopt:
+			c.action = actionOverwrite
+		default:
+			return c.skip(reasonNoBaseline), nil
+		}
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merged, conflict := merge.ThreeWay(base, current, generated)
+	if !conflict {
+		if formatted, err := format.Source(merged); err == nil {
+			merged = formatted
+		}
+	}
+	c.content = merged
+	switch {
+	case conflict:
+		c.action = actionConflict
+	case bytes.Equal(merged, current):
+		c.action = actionUnchanged
+	default:
+		c.action = actionMerge
+	}
+	return c, nil
+}
@@ -0,0 +1,176 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+const (
+	generatedV1 = "package data\n\nfunc A() {}\n"
+	generatedV2 = "package data\n\nfunc A() {}\n\nfunc B() {}\n"
+)
+
+// writeFile 写入文件, baseline 不为空时同时写入基线
+func writeFile(t *testing.T, filename, content, baseline string) {
+	t.Helper()
+	assert.NoError(t, saveFile(filename, []byte(content)))
+	if baseline != "" {
+		assert.NoError(t, saveFile(baselineFilename(filename), []byte(baseline)))
+	}
+}
+
+func TestMergeFile(t *testing.T) {
+	testData := []struct {
+		name string
+		// current 为空时文件不存在
+		current  string
+		baseline string
+		adopt    bool
+		action   string
+		content  string
+	}{
+		{
+			name:    "create",
+			action:  actionCreate,
+			content: generatedV2,
+		},
+		{
+			name:     "unchanged",
+			current:  generatedV2,
+			baseline: generatedV2,
+			action:   actionUnchanged,
+			content:  generatedV2,
+		},
+		{
+			name:     "merge",
+			current:  "// Package data is modified by hand.\npackage data\n\nfunc A() {}\n",
+			baseline: generatedV1,
+			action:   actionMerge,
+			content:  "// Package data is modified by hand.\npackage data\n\nfunc A() {}\n\nfunc B() {}\n",
+		},
+		{
+			name:     "conflict",
+			current:  "package data\n\nfunc A() {}\n\nfunc C() {}\n",
+			baseline: generatedV1,
+			action:   actionConflict,
+			content:  "package data\n\nfunc A() {}\n<<<<<<< current\n\nfunc C() {}\n=======\n\nfunc B() {}\n>>>>>>> generated\n",
+		},
+		{
+			name:     "unresolved conflict",
+			current:  "package data\n\n<<<<<<< current\nfunc C() {}\n=======\nfunc B() {}\n>>>>>>> generated\n",
+			baseline: generatedV1,
+			action:   actionSkip,
+			content:  "package data\n\n<<<<<<< current\nfunc C() {}\n=======\nfunc B() {}\n>>>>>>> generated\n",
+		},
+		{
+			name:    "no baseline unchanged",
+			current: generatedV2,
+			action:  actionUnchanged,
+			content: generatedV2,
+		},
+		{
+			name:    "no baseline",
+			current: "package data\n\n// A is modified by hand.\nfunc A() {}\n",
+			action:  actionSkip,
+			content: "package data\n\n// A is modified by hand.\nfunc A() {}\n",
+		},
+		{
+			name:    "no baseline adopt",
+			current: "package data\n\n// A is modified by hand.\nfunc A() {}\n",
+			adopt:   true,
+			action:  actionOverwrite,
+			content: generatedV2,
+		},
+	}
+	for _, v := range testData {
+		t.Run(v.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "foo.go")
+			if v.current != "" {
+				writeFile(t, filename, v.current, v.baseline)
+			}
+
+			c, err := mergeFile(filename, []byte(generatedV2), v.adopt)
+			assert.NoError(t, err)
+			assert.Equal(t, v.action, c.action)
+			assert.Equal(t, v.content, string(c.content))
+			if v.action == actionSkip {
+				assert.Nil(t, c.baseline)
+			} else {
+				assert.Equal(t, generatedV2, string(c.baseline))
+			}
+		})
+	}
+}
+
+func TestMergeFileNoBaseline(t *testing.T) {
+	// 升级前生成的文件没有基线, 删除的列不能通过合并去掉, 需要用户确认后接管
+	filename := filepath.Join(t.TempDir(), "foo.go")
+	old := "package data\n\ntype Foo struct {\n\tID   int64\n\tName string\n}\n"
+	generated := "package data\n\ntype Foo struct {\n\tID int64\n}\n"
+	writeFile(t, filename, old, "")
+
+	l := Layout{}
+	c, err := mergeFile(filename, []byte(generated), false)
+	assert.NoError(t, err)
+	assert.Equal(t, actionSkip, c.action)
+	assert.Equal(t, reasonNoBaseline, c.reason)
+	assert.NoError(t, l.apply(c))
+	assertFile(t, filename, old)
+	_, err = os.Stat(baselineFilename(filename))
+	assert.True(t, os.IsNotExist(err))
+
+	c, err = mergeFile(filename, []byte(generated), true)
+	assert.NoError(t, err)
+	assert.Equal(t, actionOverwrite, c.action)
+	assert.NoError(t, l.apply(c))
+	assertFile(t, filename, generated)
+	assertFile(t, baselineFilename(filename), generated)
+
+	// 接管后与基线做三方合并
+	assert.NoError(t, os.WriteFile(filename, []byte("// Package data is modified by hand.\n"+generated), 0o666))
+	c, err = mergeFile(filename, []byte(generated+"\nfunc B() {}\n"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, actionMerge, c.action)
+	assert.Equal(t, "// Package data is modified by hand.\n"+generated+"\nfunc B() {}\n", string(c.content))
+}
+
+func TestCheckFuncName(t *testing.T) {
+	dxl, err := parser.Parse(`CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: get_by_id
+select * from foo where id = ? limit 1;`)
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	assert.Error(t, CheckFuncName(ctx[0], map[string]struct{}{"GetById": {}}))
+	assert.NoError(t, CheckFuncName(ctx[0], map[string]struct{}{"GetByID": {}}))
+}
+
+func TestMethodName(t *testing.T) {
+	dxl, err := parser.Parse(`CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(64) NOT NULL);
+-- fn: FindByName
+select * from foo where name = ? limit 1;
+-- fn: Rename
+begin;
+-- fn: UpdateName
+update foo set name = ? where id = ?;
+commit;`)
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	methodName := MethodName(ctx[0])
+	assert.Equal(t, "FindByName", methodName("FindByName"))
+	// 事务内的语句生成为私有方法
+	assert.Equal(t, "updateName", methodName("UpdateName"))
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "data", PackageName("internal/data"))
+	assert.Equal(t, "data", PackageName("data"))
+}
@@ -0,0 +1,162 @@
+// Package gentest contains the helpers to test the code generated by the
+// backends, the generated code is compiled in a temporary module.
+package gentest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// Module 是临时模块的模块名
+const Module = "example.com/foo"
+
+// parsers 是各个方言的 sql 文件解析函数
+var parsers = map[string]func(sql string) (*spec.DXL, error){
+	types.DialectMySQL:    parser.Parse,
+	types.DialectPostgres: parser.ParsePostgres,
+	types.DialectSQLite:   parser.ParseSQLite,
+}
+
+// Context 解析 mysql 方言的 sql 文件的内容, 与 dbrepo 命令一样追加唯一键和索引生成的查询方法
+func Context(t *testing.T, sql string) []spec.Context {
+	t.Helper()
+	return DialectContext(t, types.DialectMySQL, sql)
+}
+
+// DialectContext 与 Context 相同, 但按 dialect 方言解析 sql 文件的内容
+func DialectContext(t *testing.T, dialect, sql string) []spec.Context {
+	t.Helper()
+	return parse(t, dialect, sql, nil)
+}
+
+// OverrideContext 与 Context 相同, 但解析后为表设置类型覆盖, 与 dbrepo 命令的 types 配置一致
+func OverrideContext(t *testing.T, sql string, overrides ...*spec.TypeOverride) []spec.Context {
+	t.Helper()
+	return parse(t, types.DialectMySQL, sql, func(table *spec.Table) error {
+		return table.SetTypeOverrides(overrides)
+	})
+}
+
+// NullableContext 与 Context 相同, 但解析后为表设置可空列的策略, 与 dbrepo 命令的 nullable 配置一致
+func NullableContext(t *testing.T, sql string, strategy spec.NullStrategy) []spec.Context {
+	t.Helper()
+	return parse(t, types.DialectMySQL, sql, func(table *spec.Table) error {
+		table.SetNullStrategy(strategy)
+		return nil
+	})
+}
+
+func parse(t *testing.T, dialect, sql string, set func(table *spec.Table) error) []spec.Context {
+	t.Helper()
+	dxl, err := parsers[dialect](sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ddl := range dxl.DDL {
+		if set != nil {
+			if err := set(ddl.Table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		dml, err := parser.FromConstraint(ddl.Table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dxl.AppendDML(dml...)
+	}
+	ctx, err := spec.From(dxl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+// RunArg 返回生成到 dir 的运行参数, 适配器、仓库接口和实体分别生成到临时模块的 data、service 和 entity 包
+func RunArg(dir string) types.RunArg {
+	return types.RunArg{
+		Output:        filepath.Join(dir, "data"),
+		RepoOutput:    filepath.Join(dir, "service"),
+		EntityOutput:  filepath.Join(dir, "entity"),
+		RepoPackage:   Module + "/service",
+		EntityPackage: Module + "/entity",
+	}
+}
+
+// WriteFile 把 content 写入临时模块中的 name 文件, 如 data/api_test.go,
+// 用于在生成的包中断言生成的接口或使用内存数据库运行生成的代码
+func WriteFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	filename := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0o666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Vet 在 dir 中创建临时模块并对生成的代码执行 go vet,
+// require 是生成的代码依赖的模块, 如 "github.com/jmoiron/sqlx v1.4.0"
+func Vet(t *testing.T, dir string, require ...string) {
+	t.Helper()
+	goCmd(t, dir, require, "vet", "./...")
+}
+
+// Test 在 dir 中创建临时模块并执行其中的测试, 如使用内存数据库运行生成的适配器
+func Test(t *testing.T, dir string, require ...string) {
+	t.Helper()
+	goCmd(t, dir, require, "test", "-count=1", "./...")
+}
+
+func goCmd(t *testing.T, dir string, require []string, args ...string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skip building the generated code in short mode")
+	}
+	writeModule(t, dir, require)
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	// 缺少的依赖在编译时解析并记录到 go.sum
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// writeModule 写入临时模块的 go.mod, 并复用主模块的 go.sum, 共同的依赖使用相同的校验和
+func writeModule(t *testing.T, dir string, require []string) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("module " + Module + "\n\ngo 1.22\n")
+	if len(require) > 0 {
+		b.WriteString("\nrequire (\n")
+		for _, v := range require {
+			b.WriteString("\t" + v + "\n")
+		}
+		b.WriteString(")\n")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(b.String()), 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(filepath.Dir(strings.TrimSpace(string(out))), "go.sum"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o666); err != nil {
+		t.Fatal(err)
+	}
+}
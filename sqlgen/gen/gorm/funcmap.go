@@ -1,7 +0,0 @@
-package gorm
-
-import (
-	"text/template"
-)
-
-var funcMap = template.FuncMap{}
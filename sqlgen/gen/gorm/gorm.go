@@ -1,124 +1,50 @@
 package gorm
 
 import (
-	_ "embed"
-	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
-	"text/template"
+	"embed"
 
-	"github.com/iancoleman/strcase"
-	"github.com/xyzbit/codegen/pkg/templatex"
+	"github.com/xyzbit/codegen/sqlgen/gen"
 	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
 	"github.com/xyzbit/codegen/sqlgen/pkg/types"
 )
 
-//go:embed gorm_adapter.go.tpl
-var gormAdapterTpl string
+// TemplateFS 是内置的模版, 可以通过 templates_dir 覆盖同名的模版
+//
+//go:embed *.tpl
+var TemplateFS embed.FS
 
-//go:embed gorm_repo.go.tpl
-var gormRepoTpl string
-
-//go:embed gorm_entity.go.tpl
-var gormEntityTpl string
-
-//go:embed gorm_docker_mysql_mock.go.tpl
-var gormDockerMySQLMockTpl string
-
-//go:embed gorm_sqlite_mock.go.tpl
-var gormSQLiteMockTpl string
-
-// 模版数据
+// TempData 是模版数据, 自定义模版可以依赖其中的字段, 字段只增不改
 type TempData struct {
-	spec.Context
-	AdapterPackageName string
-	RepoPackage        string
-	RepoPackageName    string
-	EntityPackage      string
-	AutoAudit          bool
+	gen.TempData
+	// SoftDelete 表中的软删除列, 不存在时为 nil
+	SoftDelete *SoftDelete
 }
 
 func Run(list []spec.Context, arg types.RunArg) error {
-	for _, ctx := range list {
-		td := TempData{
-			Context:       ctx,
-			RepoPackage:   arg.RepoPackage,
-			EntityPackage: arg.EntityPackage,
-			AutoAudit:     arg.AutoAudit,
-		}
-		adapterTmps := strings.Split(arg.Output, "/")
-		td.AdapterPackageName = adapterTmps[len(adapterTmps)-1]
-		repoTmps := strings.Split(arg.RepoOutput, "/")
-		td.RepoPackageName = repoTmps[len(repoTmps)-1]
-
-		adpterFilename := filepath.Join(arg.Output, fmt.Sprintf("%s_adpter.go", ctx.Table.Name))
-		repoFilename := filepath.Join(arg.RepoOutput, fmt.Sprintf("%s_repo.go", ctx.Table.Name))
-		entityFilename := filepath.Join(arg.EntityOutput, fmt.Sprintf("%s_entity.go", ctx.Table.Name))
-
-		// 生成基础文件
-		if err := generateFile(adpterFilename, gormAdapterTpl, td, funcMap, template.FuncMap{
-			"IsPrimary": func(name string) bool {
-				return ctx.Table.IsPrimary(name)
-			},
-			"IsExtraResult": func(name string) bool {
-				return name != strcase.ToCamel(ctx.Table.Name)
-			},
-		}); err != nil {
-			return err
-		}
-
-		if err := generateFile(repoFilename, gormRepoTpl, td, nil, nil); err != nil {
-			return err
-		}
-
-		if err := generateFile(entityFilename, gormEntityTpl, td, funcMap, nil); err != nil {
-			return err
-		}
-
-		// 根据 MockTypes 参数生成对应的 mock 文件
-		for _, mockType := range arg.MockTypes {
-			switch mockType {
-			case types.MockDocker:
-				dockerMockFilename := filepath.Join(arg.Output, fmt.Sprintf("%s_docker_mock_adapter.go", ctx.Table.Name))
-				if err := generateFile(dockerMockFilename, gormDockerMySQLMockTpl, td, funcMap, template.FuncMap{
-					"IsPrimary": func(name string) bool {
-						return ctx.Table.IsPrimary(name)
-					},
-				}); err != nil {
-					return err
-				}
-			case types.MockSQLite:
-				sqliteMockFilename := filepath.Join(arg.Output, fmt.Sprintf("%s_sqlite_mock_adapter.go", ctx.Table.Name))
-				if err := generateFile(sqliteMockFilename, gormSQLiteMockTpl, td, funcMap, template.FuncMap{
-					"IsPrimary": func(name string) bool {
-						return ctx.Table.IsPrimary(name)
-					},
-				}); err != nil {
-					return err
-				}
+	return gen.Run(list, arg, gen.Backend{
+		Name:      "gorm",
+		Templates: TemplateFS,
+		Mock:      true,
+		Table: func(td gen.TempData) (gen.Table, error) {
+			softDelete, err := newSoftDelete(td.Table, arg.SoftDelete)
+			if err != nil {
+				return gen.Table{}, err
 			}
-		}
-	}
-	return nil
-}
 
-// generateFile 生成文件的辅助函数
-func generateFile(filename string, tpl string, data interface{}, baseFuncMap template.FuncMap, extraFuncMap template.FuncMap) error {
-	if _, err := os.Stat(filename); err == nil {
-		fmt.Printf("[ignore] %s already exists\n", filename)
-		return nil
-	}
-
-	gen := templatex.New()
-	if baseFuncMap != nil {
-		gen.AppendFuncMap(baseFuncMap)
-	}
-	if extraFuncMap != nil {
-		gen.AppendFuncMap(extraFuncMap)
-	}
-	gen.MustParse(tpl)
-	gen.MustExecute(data)
-	gen.MustSaveAs(filename, true)
-	return nil
+			funcMap := associationFuncMap(td.Context)
+			for k, v := range timeFuncMap(td.Audit, arg.AutoAudit) {
+				funcMap[k] = v
+			}
+			for k, v := range softDeleteFuncMap(softDelete) {
+				funcMap[k] = v
+			}
+			// 关联和软删除生成的方法同样不能与 sql 注释中的函数名重复
+			methods := append(append([]string(nil), gen.QueryMethods...), associationMethods(td.Context)...)
+			return gen.Table{
+				Data:    TempData{TempData: td, SoftDelete: softDelete},
+				FuncMap: funcMap,
+				Methods: append(methods, softDeleteMethods(td.Table, softDelete)...),
+			}, nil
+		},
+	})
 }
@@ -9,6 +9,12 @@ import (
     "github.com/samber/lo"
     "github.com/xyzbit/gpkg/gormx"
     "github.com/xyzbit/gpkg/ctxwrap"
+    {{- if $.SoftDelete}}{{if $.SoftDelete.Plugin}}
+    "gorm.io/plugin/soft_delete"
+    {{- end}}{{end}}
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
 
     repo "{{$.RepoPackage}}"
     entity "{{$.EntityPackage}}"
@@ -39,13 +45,14 @@ func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*
     if len(es)==0{
         return fmt.Errorf("data is empty")
     }
-    {{if $.AutoAudit }}operator := ctxwrap.FromOperatorContext(ctx){{end}}
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.CreateVariables}}
+    {{- end}}
 
     pos := lo.Map(es, func(v *entity.{{UpperCamel $.Table.Name}}, _ int) *{{UpperCamel $.Table.Name}} {
-		{{- if $.AutoAudit }}
+		{{- if $.Audit.IsValid}}
         p := to{{UpperCamel $.Table.Name}}PO(ctx, v)
-		p.Creator = operator.Username
-		p.Operator = operator.Username
+        {{$.Audit.OnCreate "p"}}
 		return p
         {{- else}}
         return to{{UpperCamel $.Table.Name}}PO(ctx, v)
@@ -55,14 +62,40 @@ func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*
     return m.DB(ctx).Create(&pos).Error
 }
 
+{{if $.Table.HasCompositePrimaryKey -}}
+// GetByKey get {{$.Table.Name}} by primary key.
+func (r *{{UpperCamel $.Table.Name}}Adapter) GetByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+    {{- if $.SoftDelete}}
+
+    // the soft deleted {{$.Table.Name}} is not found.
+    {{- end}}
+    err := r.DB(ctx).
+        {{- range $.Table.PrimaryColumnList}}
+        Where("{{.Name}} = ?", key.{{UpperCamel .Name}}).
+        {{- end}}
+        First(&po).Error
+    if err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- else -}}
 // GetByID get {{$.Table.Name}} by id.
-func (r *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id int64) (*entity.{{UpperCamel $.Table.Name}}, error) {
-    var result entity.{{UpperCamel $.Table.Name}}
-    
-    err := r.DB(ctx).Where("id = ?", id).First(&result).Error
-    
-    return &result, err
+func (r *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+    {{- if $.SoftDelete}}
+
+    // the soft deleted {{$.Table.Name}} is not found.
+    {{- end}}
+    if err := r.DB(ctx).Where("{{$.Table.PrimaryColumn.Name}} = ?", id).First(&po).Error; err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
 }
+{{- end}}
 
 // List list {{$.Table.Name}}.
 func (m *{{UpperCamel $.Table.Name}}Adapter) List(ctx context.Context, query *gormx.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
@@ -95,25 +128,121 @@ func (m *{{UpperCamel $.Table.Name}}Adapter) Count(ctx context.Context, query *g
 	return count, err
 }
 
-// Update update {{$.Table.Name}}.
+{{if $.Table.HasCompositePrimaryKey -}}
+// UpdateByKey update {{$.Table.Name}} by primary key{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) UpdateByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- range $.Table.PrimaryColumnList}}
+    p.{{UpperCamel .Name}} = key.{{UpperCamel .Name}}
+    {{- end}}
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+    {{- if $.Version.IsValid}}
+    {{- template "version" $}}
+    {{- else}}
+
+    return m.DB(ctx).Updates(p).Error
+    {{- end}}
+}
+{{- else -}}
+// Update update {{$.Table.Name}}{{template "versionComment" $}}.
 func (m *{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
-	{{- if $.AutoAudit }}
-    operator := ctxwrap.FromOperatorContext(ctx)
+	{{- if or $.Audit.IsValid $.Version.IsValid}}
 	p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
-	p.Operator = operator.Username
+	{{- if $.Audit.IsValid}}
+	{{$.Audit.OnUpdate "p"}}
+	{{- end}}
+	{{- if $.Version.IsValid}}
+	{{- template "version" $}}
+	{{- else}}
 
     return m.DB(ctx).Updates(p).Error
+    {{- end}}
     {{- else}}
     return m.DB(ctx).Updates(to{{UpperCamel $.Table.Name}}PO(ctx, e)).Error
     {{- end}}
 }
+{{- end}}
 
-// Delete delete {{$.Table.Name}}.
-func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id int64) error {
+{{if $.Table.HasCompositePrimaryKey -}}
+// DeleteByKey delete {{$.Table.Name}} by primary key{{if $.SoftDelete}}, it is soft deleted by {{$.SoftDelete.Column.Name}}{{end}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    return m.DB(ctx).
+        {{- range $.Table.PrimaryColumnList}}
+        Where("{{.Name}} = ?", key.{{UpperCamel .Name}}).
+        {{- end}}
+        Delete(&{{UpperCamel $.Table.Name}}{}).Error
+}
+{{- else -}}
+// Delete delete {{$.Table.Name}}{{if $.SoftDelete}}, it is soft deleted by {{$.SoftDelete.Column.Name}}{{end}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id {{PrimaryKeyType}}) error {
 	return m.DB(ctx).
-		Where("id = ?", id).
+		Where("{{$.Table.PrimaryColumn.Name}} = ?", id).
 		Delete(&{{UpperCamel $.Table.Name}}{}).Error
 }
+{{- end}}
+{{- if $.SoftDelete}}
+{{- if $.Table.HasCompositePrimaryKey}}
+
+// HardDeleteByKey delete {{$.Table.Name}} by primary key permanently.
+func (m *{{UpperCamel $.Table.Name}}Adapter) HardDeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    return m.DB(ctx).
+        Unscoped().
+        {{- range $.Table.PrimaryColumnList}}
+        Where("{{.Name}} = ?", key.{{UpperCamel .Name}}).
+        {{- end}}
+        Delete(&{{UpperCamel $.Table.Name}}{}).Error
+}
+
+// RestoreByKey restore the soft deleted {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) RestoreByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    return m.DB(ctx).
+        Unscoped().
+        Model(&{{UpperCamel $.Table.Name}}{}).
+        {{- range $.Table.PrimaryColumnList}}
+        Where("{{.Name}} = ?", key.{{UpperCamel .Name}}).
+        {{- end}}
+        Update("{{$.SoftDelete.Column.Name}}", {{$.SoftDelete.RestoreValue}}).Error
+}
+{{- else}}
+
+// HardDelete delete {{$.Table.Name}} permanently.
+func (m *{{UpperCamel $.Table.Name}}Adapter) HardDelete(ctx context.Context, id {{PrimaryKeyType}}) error {
+    return m.DB(ctx).
+        Unscoped().
+        Where("{{$.Table.PrimaryColumn.Name}} = ?", id).
+        Delete(&{{UpperCamel $.Table.Name}}{}).Error
+}
+
+// Restore restore the soft deleted {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Restore(ctx context.Context, id {{PrimaryKeyType}}) error {
+    return m.DB(ctx).
+        Unscoped().
+        Model(&{{UpperCamel $.Table.Name}}{}).
+        Where("{{$.Table.PrimaryColumn.Name}} = ?", id).
+        Update("{{$.SoftDelete.Column.Name}}", {{$.SoftDelete.RestoreValue}}).Error
+}
+{{- end}}
+
+// ListWithDeleted list {{$.Table.Name}} including the soft deleted ones.
+func (m *{{UpperCamel $.Table.Name}}Adapter) ListWithDeleted(ctx context.Context, query *gormx.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
+    var pos []*{{UpperCamel $.Table.Name}}
+
+    err := query.
+        WithDB(m.DB(ctx).Unscoped()).
+        Find(&pos).Error
+    if err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $.Table.Name}}, _ int) *entity.{{UpperCamel $.Table.Name}} {
+        return to{{UpperCamel $.Table.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+}
+{{- end}}
 
 // IsDuplicatedKeyError use to check error is unique key conflict error.
 func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
@@ -124,10 +253,126 @@ func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) boo
 func (m *{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
 	return errors.Is(err, gorm.ErrRecordNotFound)
 }
+{{- if not $.Table.HasCompositePrimaryKey}}
+{{- range $a := Associations}}
+
+// {{GetWithMethod $a}} get {{$.Table.Name}} by id and preload {{$a.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{GetWithMethod $a}}(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+    err := m.DB(ctx).
+        Preload("{{$a.Name}}").
+        Where("{{$.Table.PrimaryColumn.Name}} = ?", id).
+        First(&po).Error
+    if err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- end}}
+{{- end}}
+{{- range $a := ListByAssociations}}
+
+// {{ListByMethod $a}} list {{$.Table.Name}} by the foreign key {{$a.ForeignKey.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{ListByMethod $a}}(ctx context.Context, {{LowerCamel $a.ForeignKey.Name}} {{$a.ForeignKey.GoType}}) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
+    var pos []*{{UpperCamel $.Table.Name}}
+    if err := m.DB(ctx).Where("{{$a.ForeignKey.Name}} = ?", {{LowerCamel $a.ForeignKey.Name}}).Find(&pos).Error; err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $.Table.Name}}, _ int) *entity.{{UpperCamel $.Table.Name}} {
+        return to{{UpperCamel $.Table.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+}
+{{- end}}
+
+{{- range $stmt := $.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{- range $tx := $.Transaction}}
+// {{UpperCamel $tx.FuncName}} is generated from sql:
+// {{LineComment $tx.SQL}}
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg repo.{{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*repo.{{$tx.ResultStructureName}}, {{end}}error) {
+    {{- if $tx.HasResult}}
+    var result repo.{{$tx.ResultStructureName}}
+    {{- end}}
+    fn := func(tx *gorm.DB) error {
+        var err error
+        txCtx := ctxwrap.NewGormDBContext(ctx, tx)
+        {{- range $v := $tx.Statements}}
+        {{- if IsInsert $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data...); err != nil {
+            return err
+        }
+        {{- else if IsSelect $v}}
+        if result.{{UpperCamel $v.FuncName}}, err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Having.IsValid}}, arg.{{UpperCamel $v.FuncName}}Having{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsUpdate $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsDelete $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- end}}
+        {{- end}}
+        return nil
+    }
+
+    // join the outer transaction if it exists.
+    var err error
+    if tx := ctxwrap.FromGormDBContext(ctx); tx != nil {
+        err = fn(tx)
+    } else {
+        err = m.db.WithContext(ctx).Transaction(fn)
+    }
+    {{- if $tx.HasResult}}
+    if err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    return err
+    {{- end}}
+}
+{{range $stmt := $tx.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{end}}
 
 // {{UpperCamel $.Table.Name}} represents a {{$.Table.Name}} struct data.
 type {{UpperCamel $.Table.Name}} struct { {{range $.Table.Columns}}
-    {{UpperCamel .Name}} {{.GoType}} `gorm:"column:{{.Name}}{{if IsPrimary .Name}};primaryKey{{end}}{{if .AutoIncrement}};autoIncrement{{end}}{{if eq .Name "created_time"}};autoCreateTime{{end}}{{if eq .Name "updated_time"}};autoUpdateTime{{end}}" json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+    {{UpperCamel .Name}} {{if IsSoftDelete .Name}}{{$.SoftDelete.GoType}}{{else}}{{.POGoType}}{{end}} `gorm:"column:{{.Name}}{{if IsPrimary .Name}};primaryKey{{end}}{{if .AutoIncrement}};autoIncrement{{end}}{{if IsCreatedTime .Name}};autoCreateTime{{end}}{{if IsUpdatedTime .Name}};autoUpdateTime{{end}}{{if IsSoftDelete .Name}}{{$.SoftDelete.Tag}}{{end}}" json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+    {{- range $.BelongsTo}}
+    {{.Name}} *{{UpperCamel .Table.Name}} `gorm:"foreignKey:{{UpperCamel .ForeignKey.Name}};references:{{UpperCamel .References.Name}}" json:"{{.JSONName}},omitempty"`
+    {{- end}}
+    {{- range $.HasMany}}
+    {{.Name}} []*{{UpperCamel .Table.Name}} `gorm:"foreignKey:{{UpperCamel .ForeignKey.Name}};references:{{UpperCamel .References.Name}}" json:"{{.JSONName}},omitempty"`
+    {{- end}}
 }
 
 // TableName returns the table name. it implemented by gorm.Tabler.
@@ -139,16 +384,205 @@ func to{{UpperCamel $.Table.Name}}PO(ctx context.Context, e *entity.{{UpperCamel
 	_ = ctx
 	return &{{UpperCamel $.Table.Name}}{
         {{- range $.Table.Columns}}
-        {{UpperCamel .Name}}: e.{{UpperCamel .Name}},
+        {{UpperCamel .Name}}: {{if IsSoftDelete .Name}}{{$.SoftDelete.ToPO "e"}}{{else}}{{.ToPO "e"}}{{end}},
         {{- end}}
     }
 }
 
 func to{{UpperCamel $.Table.Name}}Entity(ctx context.Context, po *{{UpperCamel $.Table.Name}}) *entity.{{UpperCamel $.Table.Name}} {
 	_ = ctx
+	{{- if Associations}}
+	e := &entity.{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{if IsSoftDelete .Name}}{{$.SoftDelete.ToEntity "po"}}{{else}}{{.ToEntity "po"}}{{end}},
+        {{- end}}
+    }
+    // the associations are converted only when they are preloaded.
+    {{- range $.BelongsTo}}
+    if po.{{.Name}} != nil {
+        e.{{.Name}} = to{{UpperCamel .Table.Name}}Entity(ctx, po.{{.Name}})
+    }
+    {{- end}}
+    {{- range $.HasMany}}
+    if len(po.{{.Name}}) > 0 {
+        e.{{.Name}} = lo.Map(po.{{.Name}}, func(v *{{UpperCamel .Table.Name}}, _ int) *entity.{{UpperCamel .Table.Name}} {
+            return to{{UpperCamel .Table.Name}}Entity(ctx, v)
+        })
+    }
+    {{- end}}
+
+    return e
+	{{- else}}
 	return &entity.{{UpperCamel $.Table.Name}}{
         {{- range $.Table.Columns}}
-        {{UpperCamel .Name}}: po.{{UpperCamel .Name}},
+        {{UpperCamel .Name}}: {{if IsSoftDelete .Name}}{{$.SoftDelete.ToEntity "po"}}{{else}}{{.ToEntity "po"}}{{end}},
         {{- end}}
     }
-}
\ No newline at end of file
+	{{- end}}
+}
+
+{{define "insert"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $stmt.TableInfo.Name}}) error {
+    if len(data) == 0 {
+        return fmt.Errorf("data is empty")
+    }
+
+    pos := lo.Map(data, func(v *entity.{{UpperCamel $stmt.TableInfo.Name}}, _ int) *{{UpperCamel $stmt.TableInfo.Name}} {
+        return to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, v)
+    })
+
+    return m.DB(ctx).
+        Select([]string{ {{- range $stmt.ColumnInfo}}"{{.Name}}", {{end -}} }).
+        Create(&pos).Error
+}
+
+{{end}}
+{{define "select"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having repo.{{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if IsExtraResult $stmt.ReceiverName}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error) {
+    db := m.DB(ctx).
+        Model(&{{UpperCamel $stmt.FromInfo.Name}}{}).
+        Select(`{{$stmt.SelectSQL}}`)
+    {{- if $stmt.Distinct}}
+    db = db.Distinct()
+    {{- end}}
+    {{- if $stmt.Where.IsValid}}
+    db = db.Where({{$stmt.Where.SQL}}, {{$stmt.Where.Parameters "where"}})
+    {{- end}}
+    {{- if $stmt.GroupBy.IsValid}}
+    db = db.Group({{$stmt.GroupBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Having.IsValid}}
+    db = db.Having({{$stmt.Having.SQL}}, {{$stmt.Having.Parameters "having"}})
+    {{- end}}
+    {{- if $stmt.OrderBy.IsValid}}
+    db = db.Order({{$stmt.OrderBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Limit.Multiple}}
+    db = db.Limit({{$stmt.Limit.LimitParameter "limit"}})
+    {{- if $stmt.Limit.Offset}}
+    db = db.Offset({{$stmt.Limit.OffsetParameter "limit"}})
+    {{- end}}
+    {{- else if $stmt.Limit.IsValid}}
+    {{- if $stmt.Limit.Offset}}
+    db = db.Offset({{$stmt.Limit.Offset}})
+    {{- end}}
+    {{- end}}
+    {{- if IsExtraResult $stmt.ReceiverName}}
+    {{- if $stmt.Limit.One}}
+
+    var result repo.{{$stmt.ReceiverName}}
+    if err := db.Take(&result).Error; err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    var result []*repo.{{$stmt.ReceiverName}}
+    if err := db.Find(&result).Error; err != nil {
+        return nil, err
+    }
+
+    return result, nil
+    {{- end}}
+    {{- else}}
+    {{- if $stmt.Limit.One}}
+
+    var po {{UpperCamel $stmt.FromInfo.Name}}
+    if err := db.Take(&po).Error; err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po), nil
+    {{- else}}
+
+    var pos []*{{UpperCamel $stmt.FromInfo.Name}}
+    if err := db.Find(&pos).Error; err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $stmt.FromInfo.Name}}, _ int) *entity.{{UpperCamel $stmt.FromInfo.Name}} {
+        return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+    {{- end}}
+    {{- end}}
+}
+
+{{end}}
+{{define "update"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data *entity.{{UpperCamel $stmt.TableInfo.Name}}{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    db := m.DB(ctx).
+        Model(&{{UpperCamel $stmt.TableInfo.Name}}{}).
+        Select([]string{ {{- range $stmt.ColumnInfo}}"{{.Name}}", {{end -}} })
+    {{- if $stmt.Where.IsValid}}
+    db = db.Where({{$stmt.Where.SQL}}, {{$stmt.Where.Parameters "where"}})
+    {{- else}}
+    db = db.Session(&gorm.Session{AllowGlobalUpdate: true})
+    {{- end}}
+    {{- if $stmt.OrderBy.IsValid}}
+    db = db.Order({{$stmt.OrderBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Limit.Multiple}}
+    db = db.Limit({{$stmt.Limit.LimitParameter "limit"}})
+    {{- else if $stmt.Limit.One}}
+    db = db.Limit(1)
+    {{- end}}
+
+    return db.Updates(to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, data)).Error
+}
+
+{{end}}
+{{define "delete"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    db := m.DB(ctx)
+    {{- if $stmt.Where.IsValid}}
+    db = db.Where({{$stmt.Where.SQL}}, {{$stmt.Where.Parameters "where"}})
+    {{- else}}
+    db = db.Session(&gorm.Session{AllowGlobalUpdate: true})
+    {{- end}}
+    {{- if $stmt.OrderBy.IsValid}}
+    db = db.Order({{$stmt.OrderBy.SQL}})
+    {{- end}}
+    {{- if $stmt.Limit.Multiple}}
+    db = db.Limit({{$stmt.Limit.LimitParameter "limit"}})
+    {{- else if $stmt.Limit.One}}
+    db = db.Limit(1)
+    {{- end}}
+
+    return db.Delete(&{{UpperCamel $stmt.FromInfo.Name}}{}).Error
+}
+
+{{end}}
+{{define "versionComment"}}
+{{- if $.Version.IsValid}}, {{$.Version.Column}} is increased and repo.ErrVersionConflict is returned if it is out of date{{end}}
+{{- end}}
+{{define "version"}}
+
+    // set {{$.Version.Column}} to the next version only if it is not changed by others
+    p.{{$.Version.Field}}++
+
+    result := m.DB(ctx).Where("{{$.Version.Column}} = ?", e.{{$.Version.Field}}).Updates(p)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}} = p.{{$.Version.Field}}
+
+    return nil
+{{- end}}
@@ -11,12 +11,13 @@ import (
     "gorm.io/gorm"
     "gorm.io/gorm/logger"
 
-    entity "{{$.EntityPackage}}"
     repo "{{$.RepoPackage}}"
 )
 
 // DockerMock{{UpperCamel $.Table.Name}}Adapter Docker MySQL 测试适配器
 type DockerMock{{UpperCamel $.Table.Name}}Adapter struct {
+    // 内嵌真实适配器, 复用 sql 注释生成的查询方法
+    *{{UpperCamel $.Table.Name}}Adapter
     db        *gorm.DB
     container testcontainers.Container
 }
@@ -76,12 +77,13 @@ func NewDockerMock{{UpperCamel $.Table.Name}}Repo() (repo.{{UpperCamel $.Table.N
         return nil, fmt.Errorf("failed to connect to database after %d retries: %w", maxRetries, lastErr)
     }
 
-    if err := db.AutoMigrate(&entity.{{UpperCamel $.Table.Name}}{}); err != nil {
+    if err := db.AutoMigrate(&{{UpperCamel $.Table.Name}}{}); err != nil {
         _ = container.Terminate(ctx)
         return nil, fmt.Errorf("failed to migrate table: %w", err)
     }
 
     return &DockerMock{{UpperCamel $.Table.Name}}Adapter{
+        {{UpperCamel $.Table.Name}}Adapter: &{{UpperCamel $.Table.Name}}Adapter{db: db},
         db:        db,
         container: container,
     }, nil
@@ -91,51 +93,7 @@ func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) DB(ctx context.Context) *
     return m.db.WithContext(ctx)
 }
 
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id int64) (*entity.{{UpperCamel $.Table.Name}}, error) {
-    var result entity.{{UpperCamel $.Table.Name}}
-    err := m.db.WithContext(ctx).First(&result, id).Error
-    if err != nil {
-        return nil, err
-    }
-    return &result, nil
-}
-
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, data ...*entity.{{UpperCamel $.Table.Name}}) error {
-    return m.db.WithContext(ctx).Create(&data).Error
-}
-
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) List(ctx context.Context, query *gormx.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
-    var results []*entity.{{UpperCamel $.Table.Name}}
-    db := m.db.WithContext(ctx)
-    if query != nil {
-        db = query.WithDB(db)
-    }
-    if err := db.Find(&results).Error; err != nil {
-        return nil, err
-    }
-    return results, nil
-}
-
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) Count(ctx context.Context, query *gormx.Query) (int64, error) {
-    var count int64
-    db := m.db.WithContext(ctx)
-    if query != nil {
-        db = query.WithDB(db)
-    }
-    if err := db.Model(&entity.{{UpperCamel $.Table.Name}}{}).Count(&count).Error; err != nil {
-        return 0, err
-    }
-    return count, nil
-}
-
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
-    return m.db.WithContext(ctx).Save(e).Error
-}
-
-func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id int64) error {
-    return m.db.WithContext(ctx).Delete(&entity.{{UpperCamel $.Table.Name}}{}, id).Error
-}
-
+{{/* 增删改查使用内嵌适配器的方法, 与适配器的软删除、乐观锁和类型转换语义一致 */ -}}
 func (m *DockerMock{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
     return errors.Is(err, gorm.ErrDuplicatedKey)
 }
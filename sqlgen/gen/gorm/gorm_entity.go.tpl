@@ -1,5 +1,11 @@
 package entity
-
+{{with $.Table.TypeImports}}
+import (
+    {{- range .}}
+    "{{.}}"
+    {{- end}}
+)
+{{end}}
 // {{UpperCamel $.Table.Name}} column names.
 const(
     {{range $.Table.Columns}}
@@ -11,4 +17,10 @@ type {{UpperCamel $.Table.Name}} struct {
     {{- range $.Table.Columns}}
     {{UpperCamel .Name}} {{.GoType}} `json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}
     {{- end}}
+    {{- range $.BelongsTo}}
+    {{.Name}} *{{UpperCamel .Table.Name}} `json:"{{.JSONName}},omitempty" gorm:"-"`
+    {{- end}}
+    {{- range $.HasMany}}
+    {{.Name}} []*{{UpperCamel .Table.Name}} `json:"{{.JSONName}},omitempty" gorm:"-"`
+    {{- end}}
 }
\ No newline at end of file
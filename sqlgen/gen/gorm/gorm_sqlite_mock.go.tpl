@@ -8,12 +8,13 @@ import (
     "gorm.io/gorm"
     "gorm.io/gorm/logger"
 
-    entity "{{$.EntityPackage}}"
     repo "{{$.RepoPackage}}"
 )
 
 // SQLiteMock{{UpperCamel $.Table.Name}}Adapter SQLite 测试适配器
 type SQLiteMock{{UpperCamel $.Table.Name}}Adapter struct {
+    // 内嵌真实适配器, 复用 sql 注释生成的查询方法
+    *{{UpperCamel $.Table.Name}}Adapter
     db *gorm.DB
 }
 
@@ -31,11 +32,12 @@ func NewSQLiteMock{{UpperCamel $.Table.Name}}Repo() (repo.{{UpperCamel $.Table.N
     db.Exec("PRAGMA foreign_keys = ON")
 
     // 自动迁移表结构
-    if err := db.AutoMigrate(&entity.{{UpperCamel $.Table.Name}}{}); err != nil {
+    if err := db.AutoMigrate(&{{UpperCamel $.Table.Name}}{}); err != nil {
         return nil, fmt.Errorf("failed to migrate table: %w", err)
     }
 
     return &SQLiteMock{{UpperCamel $.Table.Name}}Adapter{
+        {{UpperCamel $.Table.Name}}Adapter: &{{UpperCamel $.Table.Name}}Adapter{db: db},
         db: db,
     }, nil
 }
@@ -44,56 +46,12 @@ func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) DB(ctx context.Context) *
     return m.db.WithContext(ctx)
 }
 
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id int64) (*entity.{{UpperCamel $.Table.Name}}, error) {
-    var result entity.{{UpperCamel $.Table.Name}}
-    err := m.db.WithContext(ctx).First(&result, id).Error
-    if err != nil {
-        return nil, err
-    }
-    return &result, nil
-}
-
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, data ...*entity.{{UpperCamel $.Table.Name}}) error {
-    return m.db.WithContext(ctx).Create(&data).Error
-}
-
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) List(ctx context.Context, query *gormx.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
-    var results []*entity.{{UpperCamel $.Table.Name}}
-    db := m.db.WithContext(ctx)
-    if query != nil {
-        db = query.WithDB(db)
-    }
-    if err := db.Find(&results).Error; err != nil {
-        return nil, err
-    }
-    return results, nil
-}
-
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Count(ctx context.Context, query *gormx.Query) (int64, error) {
-    var count int64
-    db := m.db.WithContext(ctx)
-    if query != nil {
-        db = query.WithDB(db)
-    }
-    if err := db.Model(&entity.{{UpperCamel $.Table.Name}}{}).Count(&count).Error; err != nil {
-        return 0, err
-    }
-    return count, nil
-}
-
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
-    return m.db.WithContext(ctx).Save(e).Error
-}
-
-func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id int64) error {
-    return m.db.WithContext(ctx).Delete(&entity.{{UpperCamel $.Table.Name}}{}, id).Error
-}
-
+{{/* 增删改查使用内嵌适配器的方法, 与适配器的软删除、乐观锁和类型转换语义一致 */ -}}
 func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
     if err == nil {
         return false
     }
-    return err.Error() == "UNIQUE constraint failed"
+    return strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
 
 func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
@@ -101,7 +59,7 @@ func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error
 }
 
 func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Reset(ctx context.Context) error {
-    return m.db.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s", m.db.NamingStrategy.TableName("{{$.Table.Name}}"))).Error
+    return m.db.WithContext(ctx).Exec("DELETE FROM `{{$.Table.Name}}`").Error
 }
 
 func (m *SQLiteMock{{UpperCamel $.Table.Name}}Adapter) Close() error {
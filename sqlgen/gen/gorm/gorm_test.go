@@ -2,27 +2,779 @@ package gorm
 
 import (
 	_ "embed"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/gen/gentest"
 	"github.com/xyzbit/codegen/sqlgen/gen/testdata"
-	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
 	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
 	"github.com/xyzbit/codegen/sqlgen/pkg/types"
 )
 
+// require 是生成的代码依赖的模块
+var require = []string{
+	"github.com/samber/lo v1.49.1",
+	"github.com/xyzbit/gpkg v1.0.4",
+	"gorm.io/driver/sqlite v1.5.6",
+	"gorm.io/gorm v1.25.12",
+	// 旧版本的 sonic 无法在新版本的 Go 上编译
+	"github.com/bytedance/sonic v1.15.0",
+}
+
+// apiTest 在生成的包中断言生成的方法签名
+const apiTest = `package data
+
+import (
+	"context"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+var _ func(*FooAdapter, context.Context, service.FindByNameWhereParameter) (*entity.Foo, error) = (*FooAdapter).findByName
+`
+
 func TestRun(t *testing.T) {
-	dxl, err := parser.Parse(testdata.TestSql)
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, testdata.TestSql), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/api_test.go", apiTest)
+	gentest.Vet(t, dir, require...)
+}
+
+func TestRunBuild(t *testing.T) {
+	for name, layout := range map[string]string{"default": "", "split": types.LayoutSplit} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			arg := gentest.RunArg(dir)
+			arg.Layout = layout
+			arg.AutoAudit = true
+			arg.MockTypes = []string{types.MockSQLite}
+			err := Run(gentest.Context(t, testdata.BuildSql), arg)
+			assert.NoError(t, err)
+			gentest.Vet(t, dir, require...)
+		})
+	}
+}
+
+// sqliteMockTest 使用 sqlite mock 运行生成的适配器, account 表的主键是字符串
+const sqliteMockTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestAccount(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockAccountRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.Account{Uid: "u1", Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, "u1")
+	if err != nil || e.Name != "foo" {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+	e, err = r.GetByID(ctx, "u2")
+	if e != nil || !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID not found: %v, %v", e, err)
+	}
+	if err := r.Create(ctx, &entity.Account{Uid: "u1", Name: "bar"}); !r.IsDuplicatedKeyError(err) {
+		t.Fatalf("Create duplicated: %v", err)
+	}
+	if err := r.Delete(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, "u1"); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID deleted: %v", err)
+	}
+}
+
+func TestOrderItem(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockOrderItemRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.OrderItem{TenantId: 1, OrderId: "o1", Sku: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByKey(ctx, service.OrderItemKey{TenantId: 1, OrderId: "o1"})
+	if err != nil || e.Sku != "foo" {
+		t.Fatalf("GetByKey: %v, %v", e, err)
+	}
+	e, err = r.GetByKey(ctx, service.OrderItemKey{TenantId: 2, OrderId: "o1"})
+	if e != nil || !r.IsNotFoundError(err) {
+		t.Fatalf("GetByKey not found: %v, %v", e, err)
+	}
+}
+`
+
+func TestRunSQLiteMock(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	err := Run(gentest.Context(t, `CREATE TABLE account (uid varchar(32) NOT NULL, name varchar(64) NOT NULL, PRIMARY KEY (uid));
+CREATE TABLE order_item (tenant_id bigint NOT NULL, order_id varchar(64) NOT NULL, sku varchar(64) NOT NULL, PRIMARY KEY (tenant_id, order_id));`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/mock_test.go", sqliteMockTest)
+	gentest.Test(t, dir, require...)
+}
+
+func TestRunConflictFuncName(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: Count
+select count(id) AS count from foo;`), gentest.RunArg(dir))
+	assert.Error(t, err)
+}
+
+// associationTest 使用 sqlite mock 运行关联查询
+const associationTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"example.com/foo/entity"
+)
+
+func TestAssociation(t *testing.T) {
+	ctx := context.Background()
+	users, err := NewSQLiteMockUsersRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := NewSQLiteMockOrdersRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Create(ctx, &entity.Users{Id: 1, Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := orders.Create(ctx, &entity.Orders{Id: 1, UserId: 1}, &entity.Orders{Id: 2, UserId: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := users.GetUserWithOrders(ctx, 1)
+	if err != nil || len(user.Orders) != 2 {
+		t.Fatalf("GetUserWithOrders: %v, %v", user, err)
+	}
+	order, err := orders.GetOrderWithUser(ctx, 2)
+	if err != nil || order.User == nil || order.User.Name != "foo" {
+		t.Fatalf("GetOrderWithUser: %v, %v", order, err)
+	}
+	list, err := orders.ListOrdersByUserId(ctx, 1)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListOrdersByUserId: %v, %v", list, err)
+	}
+}
+`
+
+func TestRunAssociation(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	err := Run(gentest.Context(t, `CREATE TABLE users (id bigint NOT NULL primary key, name varchar(64) NOT NULL);
+CREATE TABLE orders (id bigint NOT NULL primary key, user_id bigint NOT NULL, FOREIGN KEY (user_id) REFERENCES users (id));`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/association_test.go", associationTest)
+	gentest.Test(t, dir, require...)
+}
+
+// associationIndexFinderTest 断言外键列上的索引已生成查询方法时不再生成关联的列表方法
+const associationIndexFinderTest = `package data
+
+import (
+	"reflect"
+	"testing"
+
+	"example.com/foo/service"
+)
+
+var (
+	_ = service.OrdersRepo.ListByUserId
+	_ = service.OrdersRepo.GetOrderWithUser
+)
+
+func TestAssociationIndexFinder(t *testing.T) {
+	if _, ok := reflect.TypeOf((*service.OrdersRepo)(nil)).Elem().MethodByName("ListOrdersByUserId"); ok {
+		t.Fatal("ListOrdersByUserId is generated with the index finder")
+	}
+}
+`
+
+func TestRunAssociationIndexFinder(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE users (id bigint NOT NULL primary key);
+CREATE TABLE orders (id bigint NOT NULL primary key, user_id bigint NOT NULL, KEY idx_user (user_id), FOREIGN KEY (user_id) REFERENCES users (id));`), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/association_test.go", associationIndexFinderTest)
+	gentest.Test(t, dir, require...)
+}
+
+func TestRunAssociationConflictFuncName(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE users (id bigint NOT NULL primary key);
+CREATE TABLE orders (id bigint NOT NULL primary key, user_id bigint NOT NULL, FOREIGN KEY (user_id) REFERENCES users (id));
+-- fn: ListOrdersByUserId
+select * from orders where user_id = ?;`), gentest.RunArg(dir))
+	assert.Error(t, err)
+}
+
+// structureNameTest 断言只在一张表中定义的函数不带表名前缀, 多张表中同名的函数带表名前缀
+const structureNameTest = `package data
+
+import "example.com/foo/service"
+
+var (
+	_ service.FindOneWhereParameter
+	_ service.UsersGetByEmailWhereParameter
+	_ service.OrdersGetByEmailWhereParameter
+)
+`
+
+func TestRunStructureName(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE users (id bigint NOT NULL primary key, email varchar(64) NOT NULL, UNIQUE KEY uk_email (email));
+CREATE TABLE orders (id bigint NOT NULL primary key, email varchar(64) NOT NULL, UNIQUE KEY uk_email (email));
+-- fn: FindOne
+select * from users where id = ?;`), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/api_test.go", structureNameTest)
+	gentest.Vet(t, dir, require...)
+}
+
+// operator 是审计配置的 OperatorFunc 所在的包
+const operator = `package auth
+
+import "context"
+
+type operatorKey struct{}
+
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+func Operator(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
+`
+
+// autoAuditTest 使用 sqlite mock 运行自动填充审计字段的适配器
+const autoAuditTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"example.com/foo/auth"
+	"example.com/foo/entity"
+)
+
+func TestAutoAudit(t *testing.T) {
+	ctx := auth.WithOperator(context.Background(), "lee")
+	r, err := NewSQLiteMockFooRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.Foo{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 1)
+	if err != nil || e.CreateBy != "lee" || e.GmtModified.IsZero() {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+
+	// the table without audit columns
+	bar, err := NewSQLiteMockBarRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bar.Create(ctx, &entity.Bar{Id: 1, Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+}
+`
+
+func TestRunAutoAudit(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	arg.AutoAudit = true
+	arg.Audit = types.Audit{
+		Creator:      "create_by",
+		UpdatedTime:  "gmt_modified",
+		OperatorFunc: gentest.Module + "/auth.Operator",
+	}
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key, create_by varchar(64) NOT NULL, gmt_modified datetime NOT NULL);
+CREATE TABLE bar (id bigint NOT NULL primary key, name varchar(64) NOT NULL);`), arg)
+	assert.NoError(t, err)
+
+	// the persistent object is separated from the last method by a blank line
+	adapter, err := os.ReadFile(filepath.Join(dir, "data", "bar_adpter.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(adapter), "}\n\n// Bar represents a bar struct data.")
+
+	gentest.WriteFile(t, dir, "auth/auth.go", operator)
+	gentest.WriteFile(t, dir, "data/audit_test.go", autoAuditTest)
+	gentest.Test(t, dir, require...)
+}
+
+func TestRunAutoAuditColumnType(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.AutoAudit = true
+	err := Run(gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL primary key, creator bigint NOT NULL);"), arg)
+	assert.Error(t, err)
+}
+
+// softDeleteTest 使用 sqlite mock 运行软删除的适配器
+const softDeleteTest = `package data
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/xyzbit/gpkg/gormx"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockPostRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.Post{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 1); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID deleted: %v", err)
+	}
+	list, err := r.ListWithDeleted(ctx, gormx.NewQuery())
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListWithDeleted: %v, %v", list, err)
+	}
+	if err := r.Restore(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 1); err != nil {
+		t.Fatalf("GetByID restored: %v", err)
+	}
+	if err := r.HardDelete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	list, err = r.ListWithDeleted(ctx, gormx.NewQuery())
+	if err != nil || len(list) != 0 {
+		t.Fatalf("ListWithDeleted hard deleted: %v, %v", list, err)
+	}
+}
+
+// deleted_at which is not null is not a soft delete column
+func TestNotSoftDelete(t *testing.T) {
+	if _, ok := reflect.TypeOf((*service.BarRepo)(nil)).Elem().MethodByName("HardDelete"); ok {
+		t.Fatal("HardDelete is generated without the soft delete column")
+	}
+}
+`
+
+func TestRunSoftDelete(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	err := Run(gentest.Context(t, `CREATE TABLE post (id bigint NOT NULL primary key, deleted_at datetime NULL);
+CREATE TABLE bar (id bigint NOT NULL primary key, deleted_at datetime NOT NULL);`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/soft_delete_test.go", softDeleteTest)
+	gentest.Test(t, dir, require...)
+}
+
+// TestRunSoftDeletePlugin 检查 gorm.io/plugin/soft_delete 的软删除列,
+// 生成的代码依赖该插件, 因此只检查生成的字段
+func TestRunSoftDeletePlugin(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.SoftDelete = types.SoftDelete{
+		Tables: map[string]string{"tag": "removed_at"},
+	}
+	err := Run(gentest.Context(t, `CREATE TABLE comment (id bigint NOT NULL primary key, is_deleted tinyint(1) NOT NULL DEFAULT 0);
+CREATE TABLE tag (id bigint NOT NULL primary key, removed_at bigint NOT NULL DEFAULT 0, deleted_at datetime NULL);`), arg)
+	assert.NoError(t, err)
+
+	adapter, err := os.ReadFile(filepath.Join(dir, "data", "comment_adpter.go"))
 	assert.NoError(t, err)
-	ctx, err := spec.From(dxl)
+	assert.Contains(t, string(adapter), "IsDeleted soft_delete.DeletedAt")
+	assert.Contains(t, string(adapter), "softDelete:flag")
+	assert.Contains(t, string(adapter), `Update("is_deleted", 0)`)
+
+	// the column configured for the table
+	adapter, err = os.ReadFile(filepath.Join(dir, "data", "tag_adpter.go"))
 	assert.NoError(t, err)
-	err = Run(ctx, types.RunArg{
-		Output:        t.TempDir(),
-		RepoOutput:    t.TempDir(),
-		EntityOutput:  t.TempDir(),
-		RepoPackage:   "",
-		EntityPackage: "",
-		AutoAudit:     false,
-	})
+	assert.Contains(t, string(adapter), "RemovedAt soft_delete.DeletedAt")
+	assert.NotContains(t, string(adapter), "softDelete:flag")
+	assert.NotContains(t, string(adapter), "gorm.DeletedAt")
+}
+
+func TestRunSoftDeleteColumn(t *testing.T) {
+	for _, sql := range []string{
+		"CREATE TABLE foo (id bigint NOT NULL primary key);",
+		"CREATE TABLE foo (id bigint NOT NULL primary key, removed_at datetime NOT NULL);",
+		"CREATE TABLE foo (id bigint NOT NULL primary key, removed_at varchar(64) NULL);",
+	} {
+		dir := t.TempDir()
+		arg := gentest.RunArg(dir)
+		arg.SoftDelete = types.SoftDelete{
+			Tables: map[string]string{"foo": "removed_at"},
+		}
+		err := Run(gentest.Context(t, sql), arg)
+		assert.Error(t, err, sql)
+	}
+}
+
+// optimisticLockTest 使用 sqlite mock 运行乐观锁的适配器
+const optimisticLockTest = `package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestOptimisticLock(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockFooRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.Foo{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := r.GetByID(ctx, 1)
+	stale, _ := r.GetByID(ctx, 1)
+	if err := r.Update(ctx, e); err != nil || e.Revision != 1 {
+		t.Fatalf("Update: %v, %v", e, err)
+	}
+	if err := r.Update(ctx, stale); !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("Update stale: %v", err)
+	}
+}
+
+// the column configured for the table
+func TestOptimisticLockColumn(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockBarRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := service.BarKey{TenantId: 1, Id: 1}
+	if err := r.Create(ctx, &entity.Bar{TenantId: 1, Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := r.GetByKey(ctx, key)
+	stale, _ := r.GetByKey(ctx, key)
+	if err := r.UpdateByKey(ctx, key, e); err != nil || e.LockVersion != 1 {
+		t.Fatalf("UpdateByKey: %v, %v", e, err)
+	}
+	if err := r.UpdateByKey(ctx, key, stale); !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("UpdateByKey stale: %v", err)
+	}
+}
+
+// the optimistic lock is disabled for the table
+func TestOptimisticLockDisabled(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteMockBazRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, &entity.Baz{Id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := r.GetByID(ctx, 1)
+	stale, _ := r.GetByID(ctx, 1)
+	e.Version = 1
+	stale.Version = 2
+	if err := r.Update(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, stale); err != nil {
+		t.Fatalf("Update stale: %v", err)
+	}
+}
+`
+
+func TestRunOptimisticLock(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	arg.OptimisticLock = types.OptimisticLock{
+		Tables: map[string]string{"bar": "lock_version", "baz": ""},
+	}
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key, revision bigint NOT NULL);
+CREATE TABLE bar (tenant_id bigint NOT NULL, id bigint NOT NULL, lock_version int NOT NULL, version int NOT NULL, PRIMARY KEY (tenant_id, id));
+CREATE TABLE baz (id bigint NOT NULL primary key, version int NOT NULL);`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/optimistic_lock_test.go", optimisticLockTest)
+	gentest.Test(t, dir, require...)
+}
+
+func TestRunOptimisticLockColumn(t *testing.T) {
+	for _, sql := range []string{
+		"CREATE TABLE foo (id bigint NOT NULL primary key);",
+		"CREATE TABLE foo (id bigint NOT NULL primary key, lock_version int NULL);",
+		"CREATE TABLE foo (id bigint NOT NULL primary key, lock_version varchar(32) NOT NULL);",
+	} {
+		dir := t.TempDir()
+		arg := gentest.RunArg(dir)
+		arg.OptimisticLock = types.OptimisticLock{
+			Tables: map[string]string{"foo": "lock_version"},
+		}
+		err := Run(gentest.Context(t, sql), arg)
+		assert.Error(t, err, sql)
+	}
+}
+
+// money 是类型覆盖使用的自定义类型所在的包
+const money = `package money
+
+type Money struct {
+	Cents int64
+}
+
+func ToCents(m Money) int64 {
+	return m.Cents
+}
+
+func FromCents(cents int64) Money {
+	return Money{Cents: cents}
+}
+`
+
+// typeOverridesTest 使用 sqlite mock 运行类型覆盖的适配器, 持久化对象与实体互相转换
+const typeOverridesTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"example.com/foo/entity"
+	"example.com/foo/money"
+)
+
+func TestTypeOverrides(t *testing.T) {
+	ctx := context.Background()
+	foo, err := NewSQLiteMockFooRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := foo.Create(ctx, &entity.Foo{Id: 1, Amount: money.Money{Cents: 100}}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := foo.GetByID(ctx, 1)
+	if err != nil || e.Amount.Cents != 100 {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+
+	// the type override without conversion functions
+	bar, err := NewSQLiteMockBarRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bar.Create(ctx, &entity.Bar{Id: 1, Paid: true}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bar.GetByID(ctx, 1)
+	if err != nil || !b.Paid {
+		t.Fatalf("GetByID: %v, %v", b, err)
+	}
+}
+`
+
+func TestRunTypeOverrides(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.MockTypes = []string{types.MockSQLite}
+	err := Run(gentest.OverrideContext(t, `CREATE TABLE foo (id bigint NOT NULL primary key, amount bigint NOT NULL, version int NOT NULL, deleted_at datetime DEFAULT NULL);
+CREATE TABLE bar (id bigint NOT NULL primary key, paid tinyint(1) NOT NULL);`,
+		&spec.TypeOverride{Column: "foo.amount", GoType: "money.Money", Import: gentest.Module + "/money", ToDB: "money.ToCents", FromDB: "money.FromCents"},
+		&spec.TypeOverride{DBType: "tinyint(1)", GoType: "bool"},
+	), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "money/money.go", money)
+	gentest.WriteFile(t, dir, "data/type_overrides_test.go", typeOverridesTest)
+	gentest.Test(t, dir, require...)
+}
+
+func TestRunTypeOverridesColumn(t *testing.T) {
+	for _, override := range []*spec.TypeOverride{
+		// the soft delete column is handled by gorm
+		{Column: "foo.deleted_at", GoType: "mytime.Time"},
+		// the version column is incremented by the adapter
+		{Column: "foo.version", GoType: "Version", ToDB: "int32", FromDB: "Version"},
+	} {
+		dir := t.TempDir()
+		arg := gentest.RunArg(dir)
+		arg.SoftDelete = types.SoftDelete{Tables: map[string]string{"foo": "deleted_at"}}
+		arg.OptimisticLock = types.OptimisticLock{Tables: map[string]string{"foo": "version"}}
+		err := Run(gentest.OverrideContext(t, "CREATE TABLE foo (id bigint NOT NULL primary key, version int NOT NULL, deleted_at datetime DEFAULT NULL);", override), arg)
+		assert.Error(t, err, override.Column)
+	}
+}
+
+// regenerateTest 断言重新生成后新增的列同时出现在实体和持久化对象中
+const regenerateTest = `package data
+
+import "example.com/foo/entity"
+
+var (
+	_ = entity.Foo{Age: 1}
+	_ = Foo{Age: 1}
+)
+`
+
+func TestRunRegenerate(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+
+	assert.NoError(t, Run(gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, PRIMARY KEY (id));"), arg))
+	adapterFilename := filepath.Join(dir, "data", "foo_adpter.go")
+	adapter, err := os.ReadFile(adapterFilename)
+	assert.NoError(t, err)
+	custom := "\n// Custom is written by hand.\nfunc (m *FooAdapter) Custom() {}\n"
+	assert.NoError(t, os.WriteFile(adapterFilename, append(adapter, custom...), 0o666))
+
+	assert.NoError(t, Run(gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, age int NOT NULL, PRIMARY KEY (id));"), arg))
+	adapter, err = os.ReadFile(adapterFilename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(adapter), custom)
+
+	_, err = os.Stat(filepath.Join(dir, "data", ".codegen", "foo_adpter.go"))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/regenerate_test.go", regenerateTest)
+	gentest.Vet(t, dir, require...)
+}
+
+func TestRunPreview(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	generate := func(sql string, arg types.RunArg) string {
+		ctx := gentest.Context(t, sql)
+
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		assert.NoError(t, err)
+		os.Stdout = w
+		err = Run(ctx, arg)
+		os.Stdout = stdout
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		out, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		return string(out)
+	}
+
+	generate("CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, PRIMARY KEY (id));", arg)
+	adapterFilename := filepath.Join(dir, "data", "foo_adpter.go")
+	adapter, err := os.ReadFile(adapterFilename)
+	assert.NoError(t, err)
+
+	altered := "CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, age int NOT NULL, PRIMARY KEY (id));"
+	dryRunArg := arg
+	dryRunArg.DryRun = true
+	out := generate(altered, dryRunArg)
+	assert.Contains(t, out, "[merge] "+adapterFilename+"\n")
+	assert.Contains(t, out, "[merge] "+filepath.Join(dir, "entity", "foo_entity.go")+"\n")
+	assert.Contains(t, out, "[unchanged] "+filepath.Join(dir, "service", "foo_repo.go")+"\n")
+
+	diffArg := arg
+	diffArg.Diff = true
+	out = generate(altered, diffArg)
+	assert.Contains(t, out, "--- "+adapterFilename+"\n+++ "+adapterFilename+"\n")
+	assert.Contains(t, out, "+\tAge  int32  `gorm:\"column:age\" json:\"age\"`\n")
+
+	// 预览模式不写入文件
+	current, err := os.ReadFile(adapterFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, string(adapter), string(current))
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	generate := func(sql string, arg types.RunArg) error {
+		return Run(gentest.Context(t, sql), arg)
+	}
+
+	table := `CREATE TABLE foo (
+  id bigint NOT NULL AUTO_INCREMENT,
+  a varchar(64) NOT NULL, b varchar(64) NOT NULL, c varchar(64) NOT NULL, d varchar(64) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_a (a), UNIQUE KEY uk_b (b),
+  KEY idx_c_d (c, d), KEY idx_d (d), KEY idx_b_c (b, c)
+);`
+	assert.NoError(t, generate(table, arg))
+
+	checkArg := arg
+	checkArg.Check = true
+	// 生成的代码必须是稳定的, 多次检查结果一致
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, generate(table, checkArg))
+	}
+
+	altered := strings.Replace(table, "d varchar(64) NOT NULL,", "d varchar(64) NOT NULL, e int NOT NULL,", 1)
+	assert.Error(t, generate(altered, checkArg))
+
+	// check 模式不写入文件
+	assert.NoError(t, generate(table, checkArg))
+}
+
+func TestRunTemplatesDir(t *testing.T) {
+	dir := t.TempDir()
+	templatesDir := filepath.Join(dir, "templates")
+	custom := "package entity\n\n// {{UpperCamel $.Table.Name}} is rendered by a custom template.\ntype {{UpperCamel $.Table.Name}} struct{}\n"
+	gentest.WriteFile(t, dir, "templates/gorm_entity.go.tpl", custom)
+
+	arg := gentest.RunArg(dir)
+	arg.TemplatesDir = templatesDir
+	err := Run(gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, PRIMARY KEY (id));"), arg)
+	assert.NoError(t, err)
+
+	entity, err := os.ReadFile(filepath.Join(dir, "entity", "foo_entity.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package entity\n\n// Foo is rendered by a custom template.\ntype Foo struct{}\n", string(entity))
+
+	// 未覆盖的模版使用内置模版
+	adapter, err := os.ReadFile(filepath.Join(dir, "data", "foo_adpter.go"))
 	assert.NoError(t, err)
+	assert.Contains(t, string(adapter), "func NewFooRepo(")
 }
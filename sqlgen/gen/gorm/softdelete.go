@@ -0,0 +1,156 @@
+package gorm
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// defaultSoftDeleteColumns 是未配置时的软删除列名
+var defaultSoftDeleteColumns = []string{"deleted_at", "is_deleted"}
+
+// SoftDelete 是表中的软删除列:
+//   - 可为 NULL 的时间列, 如 deleted_at datetime NULL, PO 中的类型为 gorm.DeletedAt
+//   - NOT NULL 的整数列, PO 中的类型为 soft_delete 插件的 soft_delete.DeletedAt,
+//     tinyint 为删除标记 (如 is_deleted tinyint(1)), 其余为删除时的 unix 时间戳
+type SoftDelete struct {
+	// Column 软删除列
+	Column spec.Column
+	// GoType 列在 PO 中的类型
+	GoType string
+	// Tag 列在 PO 中额外的 gorm tag, 如 ;softDelete:flag
+	Tag string
+	// Plugin 是否使用 soft_delete 插件
+	Plugin bool
+	// RestoreValue 恢复时列的值
+	RestoreValue string
+	// entityType 列在实体中的类型
+	entityType string
+}
+
+// newSoftDelete 返回表中的软删除列, 不存在时返回 nil.
+// 按表配置的列不存在或类型不符合时报错, 默认的列类型不符合时跳过.
+func newSoftDelete(table *spec.Table, config types.SoftDelete) (*SoftDelete, error) {
+	name, explicit := config.Tables[table.Name]
+	if explicit && name == "" {
+		return nil, nil
+	}
+	names := []string{name}
+	if !explicit {
+		names = config.Columns
+		if len(names) == 0 {
+			names = defaultSoftDeleteColumns
+		}
+	}
+
+	for _, name := range names {
+		c, ok := table.GetColumnByName(name)
+		if !ok {
+			if explicit {
+				return nil, fmt.Errorf("soft delete column %q is not found in table %q", name, table.Name)
+			}
+			continue
+		}
+		sd, err := softDeleteOf(table, c)
+		if err != nil {
+			if explicit {
+				return nil, err
+			}
+			continue
+		}
+		return sd, nil
+	}
+	return nil, nil
+}
+
+func softDeleteOf(table *spec.Table, c spec.Column) (*SoftDelete, error) {
+	// 软删除字段的类型由 gorm 决定, 不能覆盖
+	if c.TypeOverride != nil {
+		return nil, fmt.Errorf("go type of soft delete column %q of table %q can not be overridden", c.Name, table.Name)
+	}
+	entityType, err := c.GoType()
+	if err != nil {
+		return nil, err
+	}
+	sd := &SoftDelete{Column: c, entityType: entityType}
+	switch c.TP {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		if c.NotNull || table.IsPrimary(c.Name) {
+			return nil, fmt.Errorf("soft delete column %q of table %q must be nullable", c.Name, table.Name)
+		}
+		sd.GoType, sd.RestoreValue = "gorm.DeletedAt", "nil"
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		if !c.NotNull || table.IsPrimary(c.Name) {
+			return nil, fmt.Errorf("soft delete column %q of table %q must be not null", c.Name, table.Name)
+		}
+		sd.GoType, sd.Plugin, sd.RestoreValue = "soft_delete.DeletedAt", true, "0"
+		if c.TP == mysql.TypeTiny {
+			sd.Tag = ";softDelete:flag"
+		}
+	default:
+		return nil, fmt.Errorf("soft delete column %q of table %q must be a time or an integer", c.Name, table.Name)
+	}
+	return sd, nil
+}
+
+// ToPO 返回实体 v 的软删除字段转换为 PO 字段类型的表达式
+func (sd *SoftDelete) ToPO(v string) string {
+	field := v + "." + strcase.ToCamel(sd.Column.Name)
+	if sd.Plugin {
+		return fmt.Sprintf("soft_delete.DeletedAt(%s)", field)
+	}
+	switch sd.entityType {
+	case "*time.Time":
+		return fmt.Sprintf("gorm.DeletedAt{Time: lo.FromPtr(%s), Valid: %s != nil}", field, field)
+	case "sql.NullTime":
+		return fmt.Sprintf("gorm.DeletedAt(%s)", field)
+	case "sql.Null[time.Time]":
+		return fmt.Sprintf("gorm.DeletedAt{Time: %s.V, Valid: %s.Valid}", field, field)
+	default:
+		return fmt.Sprintf("gorm.DeletedAt{Time: %s, Valid: !%s.IsZero()}", field, field)
+	}
+}
+
+// ToEntity 返回 PO v 的软删除字段转换为实体字段类型的表达式
+func (sd *SoftDelete) ToEntity(v string) string {
+	field := v + "." + strcase.ToCamel(sd.Column.Name)
+	if sd.Plugin {
+		return fmt.Sprintf("%s(%s)", sd.entityType, field)
+	}
+	switch sd.entityType {
+	case "*time.Time":
+		return fmt.Sprintf("lo.Ternary(%s.Valid, &%s.Time, nil)", field, field)
+	case "sql.NullTime":
+		return fmt.Sprintf("sql.NullTime(%s)", field)
+	case "sql.Null[time.Time]":
+		return fmt.Sprintf("sql.Null[time.Time]{V: %s.Time, Valid: %s.Valid}", field, field)
+	default:
+		return field + ".Time"
+	}
+}
+
+// softDeleteFuncMap 返回与软删除相关的模版函数
+func softDeleteFuncMap(sd *SoftDelete) template.FuncMap {
+	return template.FuncMap{
+		"IsSoftDelete": func(name string) bool {
+			return sd != nil && sd.Column.Name == name
+		},
+	}
+}
+
+// softDeleteMethods 返回软删除生成的方法名
+func softDeleteMethods(table *spec.Table, sd *SoftDelete) []string {
+	if sd == nil {
+		return nil
+	}
+	list := []string{"HardDelete", "Restore", "ListWithDeleted"}
+	if table.HasCompositePrimaryKey() {
+		list = []string{"HardDeleteByKey", "RestoreByKey", "ListWithDeleted"}
+	}
+	return list
+}
@@ -1 +1,94 @@
 package gorm
+
+import (
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/jinzhu/inflection"
+
+	"github.com/xyzbit/codegen/sqlgen/gen"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// associationFuncMap 返回与关联相关的模版函数
+func associationFuncMap(ctx spec.Context) template.FuncMap {
+	return template.FuncMap{
+		"Associations": func() []*spec.Association {
+			return associations(ctx)
+		},
+		"GetWithMethod": func(a *spec.Association) string {
+			return getWithMethod(ctx.Table, a)
+		},
+		"ListByMethod": func(a *spec.Association) string {
+			return listByMethod(ctx.Table, a)
+		},
+		"ListByAssociations": func() []*spec.Association {
+			return listByAssociations(ctx)
+		},
+	}
+}
+
+// getWithMethod 返回预加载关联的查询方法名, 如 GetUserWithOrders
+func getWithMethod(table *spec.Table, a *spec.Association) string {
+	return "Get" + inflection.Singular(strcase.ToCamel(table.Name)) + "With" + a.Name
+}
+
+// listByMethod 返回按外键查询的方法名, 如 ListOrdersByUserId
+func listByMethod(table *spec.Table, a *spec.Association) string {
+	return "List" + inflection.Plural(strcase.ToCamel(table.Name)) + "By" + strcase.ToCamel(a.ForeignKey.Name)
+}
+
+// listByAssociations 返回需要生成按外键查询方法的 belongs_to 关联,
+// 外键列上已有索引生成的 ListByXxx 方法时不再重复生成, 如已有 ListByUserId 时不生成 ListOrdersByUserId
+func listByAssociations(ctx spec.Context) []*spec.Association {
+	finders := map[string]struct{}{}
+	for _, stmt := range ctx.SelectStmt {
+		finders[strcase.ToCamel(stmt.FuncName)] = struct{}{}
+	}
+
+	var list []*spec.Association
+	for _, a := range ctx.BelongsTo {
+		if _, ok := finders["ListBy"+strcase.ToCamel(a.ForeignKey.Name)]; ok {
+			continue
+		}
+		list = append(list, a)
+	}
+	return list
+}
+
+// associationMethods 返回关联生成的方法名, 只有单列主键的表生成预加载的查询方法
+func associationMethods(ctx spec.Context) []string {
+	var list []string
+	for _, a := range listByAssociations(ctx) {
+		list = append(list, listByMethod(ctx.Table, a))
+	}
+	if ctx.Table.HasCompositePrimaryKey() {
+		return list
+	}
+	for _, a := range associations(ctx) {
+		list = append(list, getWithMethod(ctx.Table, a))
+	}
+	return list
+}
+
+// associations 返回表的所有关联, belongs_to 在前
+func associations(ctx spec.Context) []*spec.Association {
+	return append(append([]*spec.Association(nil), ctx.BelongsTo...), ctx.HasMany...)
+}
+
+// timeFuncMap 返回判断 gorm 自动维护的时间列的模版函数,
+// 未开启自动审计时沿用 created_time 和 updated_time
+func timeFuncMap(audit gen.Audit, autoAudit bool) template.FuncMap {
+	createdTime, updatedTime := "created_time", "updated_time"
+	if autoAudit {
+		createdTime, updatedTime = audit.CreatedTime, audit.UpdatedTime
+	}
+	return template.FuncMap{
+		"IsCreatedTime": func(name string) bool {
+			return name == createdTime
+		},
+		"IsUpdatedTime": func(name string) bool {
+			return name == updatedTime
+		},
+	}
+}
@@ -0,0 +1,165 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/xyzbit/codegen/pkg/templatex"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// generatedHeader 是完全由工具维护的文件的头部注释, 遵循 go 的生成代码约定
+const generatedHeader = "// Code generated by codegen. DO NOT EDIT.\n\n"
+
+// Layout 决定生成文件的组织方式:
+//   - 默认布局: 生成的代码与自定义代码在同一个文件中, 重新生成时做三方合并
+//   - 分离布局: 生成的代码保存在 _gen.go 文件中, 每次都会覆盖;
+//     自定义代码写在只生成一次的脚手架文件中
+//
+// 同时决定生成的内容是写入文件, 还是只预览变更 (dry-run, diff) 或检查是否过期 (check).
+type Layout struct {
+	split  bool
+	dryRun bool
+	diff   bool
+	// adopt 是否接管没有基线的存量文件
+	adopt bool
+	// templates 是后端内置的模版, templatesDir 中的同名模版优先
+	templates    fs.FS
+	templatesDir string
+	// stale 记录 check 模式下与生成结果不一致的文件
+	stale *[]string
+}
+
+// NewLayout 根据运行参数返回文件布局, templates 是后端内置的模版
+func NewLayout(arg types.RunArg, templates fs.FS) Layout {
+	return Layout{
+		split:        arg.Layout == types.LayoutSplit,
+		dryRun:       arg.DryRun,
+		diff:         arg.Diff,
+		adopt:        arg.Adopt,
+		templates:    templates,
+		templatesDir: arg.TemplatesDir,
+		stale:        staleFiles(arg.Check),
+	}
+}
+
+func staleFiles(check bool) *[]string {
+	if !check {
+		return nil
+	}
+	return &[]string{}
+}
+
+// Err 返回 check 模式下的检查结果, 存在过期的文件时返回错误
+func (l Layout) Err() error {
+	if l.stale == nil || len(*l.stale) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d generated files are out of date, please regenerate them", len(*l.stale))
+}
+
+// Split 返回是否为分离布局
+func (l Layout) Split() bool {
+	return l.split
+}
+
+// Filename 返回生成文件的文件名, name 不包含扩展名
+func (l Layout) Filename(dir, name string) string {
+	if l.split {
+		return filepath.Join(dir, name+"_gen.go")
+	}
+	return filepath.Join(dir, name+".go")
+}
+
+// AdapterFilename 返回适配器文件的文件名,
+// 默认布局沿用 adpter 的拼写, 避免已生成的文件被重复生成.
+func (l Layout) AdapterFilename(dir, table string) string {
+	if l.split {
+		return l.Filename(dir, table+"_adapter")
+	}
+	return l.Filename(dir, table+"_adpter")
+}
+
+// GenerateFile 使用名为 name 的模版生成文件.
+// 默认布局下文件已存在时与上一次生成的内容 (基线) 做三方合并, 新增的列和方法会合并到文件中,
+// 同时保留手写的修改; 分离布局下添加生成代码的头部注释并覆盖已存在的文件.
+func (l Layout) GenerateFile(filename string, name string, data interface{}, funcMaps ...template.FuncMap) error {
+	tpl, err := loadTemplate(l.templatesDir, l.templates, name)
+	if err != nil {
+		return err
+	}
+	generated := render(tpl, data, funcMaps...)
+	if !l.split {
+		c, err := mergeFile(filename, generated, l.adopt)
+		if err != nil {
+			return err
+		}
+		return l.apply(c)
+	}
+
+	c := &fileChange{filename: filename, content: append([]byte(generatedHeader), generated...)}
+	current, err := os.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+		c.action = actionCreate
+	case err != nil:
+		return err
+	case bytes.Equal(current, c.content):
+		c.action, c.current = actionUnchanged, current
+	default:
+		c.action, c.current = actionOverwrite, current
+	}
+	return l.apply(c)
+}
+
+// Scaffold 在分离布局下生成适配器和仓库接口的脚手架文件, 文件已存在时跳过
+func (l Layout) Scaffold(adapterDir, repoDir, table string, data interface{}) error {
+	if !l.split {
+		return nil
+	}
+	if err := l.scaffold(filepath.Join(adapterDir, table+"_adapter.go"), "scaffold_adapter.go.tpl", data); err != nil {
+		return err
+	}
+	return l.scaffold(filepath.Join(repoDir, table+"_repo.go"), "scaffold_repo.go.tpl", data)
+}
+
+func (l Layout) scaffold(filename, name string, data interface{}) error {
+	current, err := os.ReadFile(filename)
+	if err == nil {
+		if !l.writable() {
+			return l.apply((&fileChange{filename: filename, current: current}).skip(reasonScaffoldExists))
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	tpl, err := loadTemplate(l.templatesDir, TemplateFS, name)
+	if err != nil {
+		return err
+	}
+	if l.writable() {
+		fmt.Printf("[scaffold] %s\n", filename)
+	}
+	return l.apply(&fileChange{filename: filename, action: actionCreate, content: render(tpl, data)})
+}
+
+// render 渲染模版并格式化代码
+func render(tpl string, data interface{}, funcMaps ...template.FuncMap) []byte {
+	g := templatex.New()
+	for _, fm := range funcMaps {
+		if fm != nil {
+			g.AppendFuncMap(fm)
+		}
+	}
+	g.MustParse(tpl)
+	g.MustExecute(data)
+
+	var buf bytes.Buffer
+	g.Write(&buf, true)
+	return buf.Bytes()
+}
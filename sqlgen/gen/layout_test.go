@@ -0,0 +1,105 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// templates 是测试用的后端模版
+var templates = fstest.MapFS{
+	"foo.go.tpl": {Data: []byte("package {{.}}\n\nfunc A() {}\n")},
+}
+
+func TestFilename(t *testing.T) {
+	l := NewLayout(types.RunArg{}, templates)
+	assert.Equal(t, filepath.Join("data", "foo.go"), l.Filename("data", "foo"))
+	assert.Equal(t, filepath.Join("data", "foo_adpter.go"), l.AdapterFilename("data", "foo"))
+
+	l = NewLayout(types.RunArg{Layout: types.LayoutSplit}, templates)
+	assert.Equal(t, filepath.Join("data", "foo_gen.go"), l.Filename("data", "foo"))
+	assert.Equal(t, filepath.Join("data", "foo_adapter_gen.go"), l.AdapterFilename("data", "foo"))
+}
+
+func TestGenerateFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "foo.go")
+	l := NewLayout(types.RunArg{}, templates)
+
+	assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+	assertFile(t, filename, "package data\n\nfunc A() {}\n")
+	assertFile(t, baselineFilename(filename), "package data\n\nfunc A() {}\n")
+
+	// 手写的修改与新生成的方法合并
+	assert.NoError(t, os.WriteFile(filename, []byte("// Package data is modified by hand.\npackage data\n\nfunc A() {}\n"), 0o666))
+	l.templates = fstest.MapFS{"foo.go.tpl": {Data: []byte("package {{.}}\n\nfunc A() {}\n\nfunc B() {}\n")}}
+	assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+	assertFile(t, filename, "// Package data is modified by hand.\npackage data\n\nfunc A() {}\n\nfunc B() {}\n")
+	assertFile(t, baselineFilename(filename), "package data\n\nfunc A() {}\n\nfunc B() {}\n")
+}
+
+func TestGenerateFileSplit(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "foo_gen.go")
+	l := NewLayout(types.RunArg{Layout: types.LayoutSplit}, templates)
+
+	assert.NoError(t, os.WriteFile(filename, []byte("package data\n\nfunc C() {}\n"), 0o666))
+	assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+	// 分离布局覆盖已存在的文件, 不保存基线
+	assertFile(t, filename, generatedHeader+"package data\n\nfunc A() {}\n")
+	_, err := os.Stat(baselineFilename(filename))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateFileTemplatesDir(t *testing.T) {
+	dir := t.TempDir()
+	templatesDir := filepath.Join(dir, "templates")
+	assert.NoError(t, os.Mkdir(templatesDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(templatesDir, "foo.go.tpl"), []byte("package {{.}}\n\nfunc Custom() {}\n"), 0o666))
+
+	filename := filepath.Join(dir, "foo.go")
+	l := NewLayout(types.RunArg{TemplatesDir: templatesDir}, templates)
+	assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+	assertFile(t, filename, "package data\n\nfunc Custom() {}\n")
+
+	assert.Error(t, l.GenerateFile(filename, "bar.go.tpl", "data"))
+}
+
+func TestScaffold(t *testing.T) {
+	dxl, err := parser.Parse("CREATE TABLE foo (id bigint NOT NULL primary key);")
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	data := TempData{Context: ctx[0], AdapterPackageName: "data", RepoPackageName: "service"}
+
+	l := NewLayout(types.RunArg{}, templates)
+	assert.NoError(t, l.Scaffold(dir, dir, "foo", data))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "default layout has no scaffold")
+
+	l = NewLayout(types.RunArg{Layout: types.LayoutSplit}, templates)
+	filename := filepath.Join(dir, "foo_repo.go")
+	assert.NoError(t, os.WriteFile(filename, []byte("package service\n"), 0o666))
+	assert.NoError(t, l.Scaffold(dir, dir, "foo", data))
+	// 已存在的脚手架文件不会被覆盖
+	assertFile(t, filename, "package service\n")
+	adapter, err := os.ReadFile(filepath.Join(dir, "foo_adapter.go"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(adapter), "package data\n"))
+}
+
+func assertFile(t *testing.T, filename, content string) {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
@@ -0,0 +1,144 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// 文件变更的类型
+const (
+	actionCreate    = "create"
+	actionOverwrite = "overwrite"
+	actionMerge     = "merge"
+	actionConflict  = "conflict"
+	actionUnchanged = "unchanged"
+	actionSkip      = "skip"
+)
+
+const (
+	reasonScaffoldExists = "scaffold already exists"
+	reasonNoBaseline     = "has no baseline, preview it with --diff --adopt and take it over with --adopt"
+)
+
+// fileChange 描述一次生成对文件的变更
+type fileChange struct {
+	filename string
+	action   string
+	// reason 是跳过的原因
+	reason string
+	// current 是文件已存在时的内容
+	current []byte
+	// content 是写入文件的内容
+	content []byte
+	// baseline 是需要保存的基线, 为空时不保存
+	baseline []byte
+}
+
+func (c *fileChange) skip(reason string) *fileChange {
+	c.action = actionSkip
+	c.reason = reason
+	c.content = c.current
+	c.baseline = nil
+	return c
+}
+
+// apply 根据运行模式写入文件, 或者只输出将要进行的变更
+func (l Layout) apply(c *fileChange) error {
+	switch {
+	case l.stale != nil:
+		l.check(c)
+		return nil
+	case l.dryRun:
+		if c.reason != "" {
+			fmt.Printf("[%s] %s: %s\n", c.action, c.filename, c.reason)
+		} else {
+			fmt.Printf("[%s] %s\n", c.action, c.filename)
+		}
+		return nil
+	case l.diff:
+		return printDiff(c)
+	}
+
+	switch c.action {
+	case actionSkip:
+		fmt.Printf("[ignore] %s %s\n", c.filename, c.reason)
+	case actionMerge:
+		fmt.Printf("[merge] %s\n", c.filename)
+	case actionConflict:
+		fmt.Printf("[conflict] %s, please resolve the conflicts manually\n", c.filename)
+	}
+	if c.action != actionSkip && !bytes.Equal(c.content, c.current) {
+		if err := saveFile(c.filename, c.content); err != nil {
+			return err
+		}
+	}
+	if c.baseline != nil {
+		return saveFile(baselineFilename(c.filename), c.baseline)
+	}
+	return nil
+}
+
+// writable 返回是否需要写入文件
+func (l Layout) writable() bool {
+	return !l.dryRun && !l.diff && l.stale == nil
+}
+
+// check 检查文件及其基线是否与本次生成的结果一致,
+// 没有基线的存量文件 (如升级前生成的文件) 无法判断是否过期, 单独报告且不视为过期
+func (l Layout) check(c *fileChange) {
+	if c.action == actionSkip && c.reason == reasonNoBaseline {
+		fmt.Printf("[no-baseline] %s\n", c.filename)
+		return
+	}
+	stale := c.action != actionUnchanged
+	if c.action == actionSkip && c.reason == reasonScaffoldExists {
+		stale = false
+	}
+	if !stale && c.baseline != nil {
+		base, err := os.ReadFile(baselineFilename(c.filename))
+		if os.IsNotExist(err) {
+			// 文件与生成的结果一致, 只缺少基线
+			fmt.Printf("[no-baseline] %s\n", c.filename)
+			return
+		}
+		stale = err != nil || !bytes.Equal(base, c.baseline)
+	}
+	if stale {
+		fmt.Printf("[stale] %s\n", c.filename)
+		*l.stale = append(*l.stale, c.filename)
+	}
+}
+
+// printDiff 输出已存在的文件与生成内容之间的 unified diff
+func printDiff(c *fileChange) error {
+	if bytes.Equal(c.current, c.content) {
+		return nil
+	}
+	from := c.filename
+	if c.action == actionCreate {
+		from = os.DevNull
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(c.current)),
+		B:        difflib.SplitLines(string(c.content)),
+		FromFile: from,
+		ToFile:   c.filename,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+func saveFile(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o666)
+}
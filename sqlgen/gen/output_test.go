@@ -0,0 +1,83 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+func TestApplyPreview(t *testing.T) {
+	for name, arg := range map[string]types.RunArg{
+		"dry-run": {DryRun: true},
+		"diff":    {Diff: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := NewLayout(arg, templates)
+			assert.False(t, l.writable())
+
+			// 预览时不写入文件和基线
+			assert.NoError(t, l.GenerateFile(filepath.Join(dir, "foo.go"), "foo.go.tpl", "data"))
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestApplySkip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo.go")
+	writeFile(t, filename, "package data\n\n<<<<<<< current\n", "package data\n")
+
+	l := NewLayout(types.RunArg{}, templates)
+	assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+	// 存在未解决的冲突时文件和基线都不变
+	assertFile(t, filename, "package data\n\n<<<<<<< current\n")
+	assertFile(t, baselineFilename(filename), "package data\n")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "foo.go")
+	generated := "package data\n\nfunc A() {}\n"
+
+	testData := []struct {
+		name     string
+		current  string
+		baseline string
+		stale    bool
+	}{
+		{name: "missing", stale: true},
+		{name: "up to date", current: generated, baseline: generated},
+		{name: "missing baseline", current: generated},
+		{name: "missing baseline modified", current: "package data\n\nfunc A() { println() }\n"},
+		{name: "stale baseline", current: generated, baseline: "package data\n", stale: true},
+		{name: "modified", current: "package data\n\nfunc A() { println() }\n", baseline: "package data\n\nfunc A() { println() }\n", stale: true},
+	}
+	for _, v := range testData {
+		t.Run(v.name, func(t *testing.T) {
+			assert.NoError(t, os.RemoveAll(dir))
+			if v.current != "" {
+				writeFile(t, filename, v.current, v.baseline)
+			}
+
+			l := NewLayout(types.RunArg{Check: true}, templates)
+			assert.NoError(t, l.GenerateFile(filename, "foo.go.tpl", "data"))
+			if v.stale {
+				assert.Equal(t, []string{filename}, *l.stale)
+				assert.Error(t, l.Err())
+			} else {
+				assert.Empty(t, *l.stale)
+				assert.NoError(t, l.Err())
+			}
+			// 检查时不写入文件
+			if v.current == "" {
+				_, err := os.Stat(filename)
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package gen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// QueryFuncMap 返回需要手写 sql 的后端 (sqlx, database/sql, xorm) 共用的模版函数, 标识符按方言加引号
+func QueryFuncMap(dialect string) template.FuncMap {
+	return template.FuncMap{
+		"Query": func(dml spec.DML) (string, error) { return Query(dml, dialect) },
+		"Args":  Args,
+	}
+}
+
+// TableQueryFuncMap 返回 QueryFuncMap 和内置方法使用的 sql 以及加引号的表名 (QuotedTable) 和列名列表 (QuotedColumns), 返回值是 go 字符串字面量,
+// Named 前缀的 sql 使用 :column 命名参数, 其余使用 ? 占位符, 由适配器在执行前替换为数据库的占位符.
+// 按主键查询的条件包含所有主键列, 参数顺序与 Table.PrimaryColumnList 一致.
+// 更新与 gorm 的 Updates 一致不更新零值字段: UpdateColumns 返回可更新的列, 不包含主键、版本列以及创建人和创建时间等审计列,
+// 适配器按字段是否为零值拼接 set 子句, UpdateSQL 返回拼接更新语句的 go 表达式, 条件中的版本在主键之后.
+func TableQueryFuncMap(td TempData) template.FuncMap {
+	table, audit, version := td.Table, td.Audit, td.Version
+	q := quoter(td.Dialect)
+	primaryWhere := func(named bool) string {
+		var conditions []string
+		for _, c := range table.PrimaryColumnList() {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", q(c.Name), placeholder(c.Name, named)))
+		}
+		return strings.Join(conditions, " AND ")
+	}
+	insertSQL := func(named bool) string {
+		var columns, values []string
+		for _, c := range table.Columns {
+			if c.AutoIncrement {
+				continue
+			}
+			columns = append(columns, q(c.Name))
+			values = append(values, placeholder(c.Name, named))
+		}
+		return strconv.Quote(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", q(table.Name), strings.Join(columns, ", "), strings.Join(values, ", ")))
+	}
+	var updateColumns []UpdateColumn
+	for _, c := range table.Columns {
+		if table.IsPrimary(c.Name) || audit.IsImmutable(c.Name) || version.Is(c.Name) {
+			continue
+		}
+		updateColumns = append(updateColumns, UpdateColumn{Field: strcase.ToCamel(c.Name), Set: strconv.Quote(q(c.Name) + " = ?")})
+	}
+	updateSQL := func(sets string) string {
+		where := primaryWhere(false)
+		if version.IsValid() {
+			where += fmt.Sprintf(" AND %s = ?", q(version.Column))
+		}
+		return fmt.Sprintf("%s + strings.Join(%s, \", \") + %s", strconv.Quote("UPDATE "+q(table.Name)+" SET "), sets, strconv.Quote(" WHERE "+where))
+	}
+	funcMap := QueryFuncMap(td.Dialect)
+	funcMap["InsertSQL"] = func() string { return insertSQL(false) }
+	funcMap["NamedInsertSQL"] = func() string { return insertSQL(true) }
+	funcMap["UpdateColumns"] = func() []UpdateColumn { return updateColumns }
+	funcMap["VersionSetSQL"] = func() string {
+		return strconv.Quote(fmt.Sprintf("%s = %s + 1", q(version.Column), q(version.Column)))
+	}
+	funcMap["UpdateSQL"] = updateSQL
+	var columns []string
+	for _, c := range table.Columns {
+		columns = append(columns, q(c.Name))
+	}
+	funcMap["QuotedTable"] = func() string { return strconv.Quote(q(table.Name)) }
+	funcMap["QuotedColumns"] = func() string { return strconv.Quote(strings.Join(columns, ", ")) }
+	funcMap["GetByIDSQL"] = func() string {
+		return strconv.Quote(fmt.Sprintf("SELECT %s FROM %s WHERE %s LIMIT 1", strings.Join(columns, ", "), q(table.Name), primaryWhere(false)))
+	}
+	funcMap["DeleteByIDSQL"] = func() string {
+		return strconv.Quote(fmt.Sprintf("DELETE FROM %s WHERE %s", q(table.Name), primaryWhere(false)))
+	}
+	return funcMap
+}
+
+// UpdateColumn 是更新语句中可更新的列
+type UpdateColumn struct {
+	// Field 是列在 PO 中的字段名
+	Field string
+	// Set 是列的 set 子句, 为 go 字符串字面量, 如 "`name` = ?"
+	Set string
+}
+
+// quote 按方言为标识符加引号, mysql 使用反引号, postgres 和 sqlite 使用双引号
+func quote(dialect, name string) string {
+	switch dialect {
+	case types.DialectPostgres, types.DialectSQLite:
+		return `"` + name + `"`
+	default:
+		return "`" + name + "`"
+	}
+}
+
+// quoter 返回按方言为标识符加引号的函数
+func quoter(dialect string) func(name string) string {
+	return func(name string) string { return quote(dialect, name) }
+}
+
+func placeholder(column string, named bool) string {
+	if named {
+		return ":" + column
+	}
+	return "?"
+}
+
+// Query 根据语句重新生成带 ? 占位符的 sql, 返回值是 go 字符串字面量,
+// 返回多行的 limit 和 offset 也会被替换为占位符, 表名和列名按方言加引号.
+func Query(dml spec.DML, dialect string) (string, error) {
+	var (
+		sql string
+		err error
+	)
+	switch stmt := dml.(type) {
+	case *spec.InsertStmt:
+		sql = insertQuery(stmt, dialect)
+	case *spec.SelectStmt:
+		sql, err = selectQuery(stmt, dialect)
+	case *spec.UpdateStmt:
+		sql, err = updateQuery(stmt, dialect)
+	case *spec.DeleteStmt:
+		sql, err = deleteQuery(stmt, dialect)
+	default:
+		err = fmt.Errorf("unsupported statement: %T", dml)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strconv.Quote(sql), nil
+}
+
+// Args 返回 Query 中占位符对应的参数, 变量名与适配器模版中的参数名保持一致:
+// data 为 insert/update 的数据, where/having/limit 为对应的参数结构.
+func Args(dml spec.DML, data string) (string, error) {
+	list, err := argList(dml, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(list, ", "), nil
+}
+
+// argList 按占位符顺序返回 Args 的参数列表, 类型覆盖了转换函数的参数是包含逗号的表达式,
+// 因此不能通过拆分 Args 的返回值得到.
+func argList(dml spec.DML, data string) ([]string, error) {
+	var list []string
+	switch stmt := dml.(type) {
+	case *spec.InsertStmt:
+		for _, v := range stmt.ColumnInfo {
+			list = append(list, fmt.Sprintf("%s.%s", data, strcase.ToCamel(v.Name)))
+		}
+	case *spec.UpdateStmt:
+		for _, v := range stmt.ColumnInfo {
+			list = append(list, fmt.Sprintf("%s.%s", data, strcase.ToCamel(v.Name)))
+		}
+	case *spec.SelectStmt, *spec.DeleteStmt:
+	default:
+		return nil, fmt.Errorf("unsupported statement: %T", dml)
+	}
+
+	for _, v := range clauses(dml) {
+		args, err := v.clause.ParameterList(v.pkg)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, args...)
+	}
+	list = append(list, limitArgs(dml)...)
+	return list, nil
+}
+
+// InArgs 返回语句中 in 和 not in 表达式的参数, 参数名与 Args 一致.
+func InArgs(dml spec.DML) ([]string, error) {
+	var list []string
+	for _, v := range clauses(dml) {
+		args, err := v.clause.InParameters(v.pkg)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, args...)
+	}
+	return list, nil
+}
+
+// HasIn 判断语句中是否包含 in 或 not in 表达式
+func HasIn(dml spec.DML) (bool, error) {
+	args, err := InArgs(dml)
+	return len(args) > 0, err
+}
+
+// WrapInArgs 与 Args 相同, 但 in 和 not in 的参数使用 wrapper 函数包装,
+// 用于在执行前展开 in 表达式的占位符.
+func WrapInArgs(dml spec.DML, data, wrapper string) (string, error) {
+	list, err := argList(dml, data)
+	if err != nil {
+		return "", err
+	}
+	inArgs, err := InArgs(dml)
+	if err != nil {
+		return "", err
+	}
+	return wrapIn(list, inArgs, wrapper), nil
+}
+
+// WrapInClauseArgs 返回单个 where/having 子句的参数, pkg 为参数结构的变量名,
+// in 和 not in 的参数使用 wrapper 函数包装, 用于分别传入子句参数的后端 (如 bun).
+func WrapInClauseArgs(clause *spec.Clause, pkg, wrapper string) (string, error) {
+	list, err := clause.ParameterList(pkg)
+	if err != nil {
+		return "", err
+	}
+	inArgs, err := clause.InParameters(pkg)
+	if err != nil {
+		return "", err
+	}
+	return wrapIn(list, inArgs, wrapper), nil
+}
+
+func wrapIn(list, inArgs []string, wrapper string) string {
+	in := make(map[string]struct{}, len(inArgs))
+	for _, v := range inArgs {
+		in[v] = struct{}{}
+	}
+	for i, v := range list {
+		if _, ok := in[v]; ok {
+			list[i] = fmt.Sprintf("%s(%s)", wrapper, v)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// clauseArg 是子句及其参数结构的变量名
+type clauseArg struct {
+	clause *spec.Clause
+	pkg    string
+}
+
+// clauses 按占位符顺序返回语句的 where/having 子句
+func clauses(dml spec.DML) []clauseArg {
+	switch stmt := dml.(type) {
+	case *spec.SelectStmt:
+		return []clauseArg{{stmt.Where, "where"}, {stmt.Having, "having"}}
+	case *spec.UpdateStmt:
+		return []clauseArg{{stmt.Where, "where"}}
+	case *spec.DeleteStmt:
+		return []clauseArg{{stmt.Where, "where"}}
+	}
+	return nil
+}
+
+func insertQuery(stmt *spec.InsertStmt, dialect string) string {
+	var columns, values []string
+	for _, v := range stmt.ColumnInfo {
+		columns = append(columns, quote(dialect, v.Name))
+		values = append(values, "?")
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quote(dialect, stmt.Table), strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+func selectQuery(stmt *spec.SelectStmt, dialect string) (string, error) {
+	q := quoter(dialect)
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	if stmt.Distinct {
+		b.WriteString("DISTINCT ")
+	}
+	// 通配符展开为列名, 保证查询结果的列顺序与 ColumnInfo 一致
+	var fields []string
+	for _, v := range stmt.Columns {
+		if v.ColumnName != spec.WildCard {
+			fields = append(fields, v.QuotedSQL(q))
+			continue
+		}
+		for _, c := range stmt.FromInfo.Columns {
+			fields = append(fields, q(c.Name))
+		}
+	}
+	fmt.Fprintf(&b, "%s FROM %s", strings.Join(fields, ", "), q(stmt.From))
+	if err := writeClause(&b, "WHERE", stmt.Where, q); err != nil {
+		return "", err
+	}
+	if err := writeByItems(&b, "GROUP BY", stmt.GroupBy, q); err != nil {
+		return "", err
+	}
+	if err := writeClause(&b, "HAVING", stmt.Having, q); err != nil {
+		return "", err
+	}
+	if err := writeByItems(&b, "ORDER BY", stmt.OrderBy, q); err != nil {
+		return "", err
+	}
+	writeLimit(&b, stmt.Limit)
+	return b.String(), nil
+}
+
+func updateQuery(stmt *spec.UpdateStmt, dialect string) (string, error) {
+	var b strings.Builder
+	var sets []string
+	for _, v := range stmt.ColumnInfo {
+		sets = append(sets, fmt.Sprintf("%s = ?", quote(dialect, v.Name)))
+	}
+	fmt.Fprintf(&b, "UPDATE %s SET %s", quote(dialect, stmt.Table), strings.Join(sets, ", "))
+	if err := writeClause(&b, "WHERE", stmt.Where, quoter(dialect)); err != nil {
+		return "", err
+	}
+	if err := writeByItems(&b, "ORDER BY", stmt.OrderBy, quoter(dialect)); err != nil {
+		return "", err
+	}
+	writeLimit(&b, stmt.Limit)
+	return b.String(), nil
+}
+
+func deleteQuery(stmt *spec.DeleteStmt, dialect string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "DELETE FROM %s", quote(dialect, stmt.From))
+	if err := writeClause(&b, "WHERE", stmt.Where, quoter(dialect)); err != nil {
+		return "", err
+	}
+	if err := writeByItems(&b, "ORDER BY", stmt.OrderBy, quoter(dialect)); err != nil {
+		return "", err
+	}
+	writeLimit(&b, stmt.Limit)
+	return b.String(), nil
+}
+
+func writeClause(b *strings.Builder, keyword string, clause *spec.Clause, quote func(string) string) error {
+	if !clause.IsValid() {
+		return nil
+	}
+	sql, err := clause.QuotedSQL(quote)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, " %s %s", keyword, sql)
+	return nil
+}
+
+func writeByItems(b *strings.Builder, keyword string, items spec.ByItems, quote func(string) string) error {
+	if !items.IsValid() {
+		return nil
+	}
+	sql, err := items.QuotedSQL(quote)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, " %s %s", keyword, sql)
+	return nil
+}
+
+func writeLimit(b *strings.Builder, limit *spec.Limit) {
+	switch {
+	case limit.Multiple():
+		b.WriteString(" LIMIT ?")
+		if limit.Offset > 0 {
+			b.WriteString(" OFFSET ?")
+		}
+	case limit.IsValid():
+		fmt.Fprintf(b, " LIMIT %d", limit.Count)
+		if limit.Offset > 0 {
+			fmt.Fprintf(b, " OFFSET %d", limit.Offset)
+		}
+	}
+}
+
+func limitArgs(dml spec.DML) []string {
+	var limit *spec.Limit
+	switch stmt := dml.(type) {
+	case *spec.SelectStmt:
+		limit = stmt.Limit
+	case *spec.UpdateStmt:
+		limit = stmt.Limit
+	case *spec.DeleteStmt:
+		limit = stmt.Limit
+	}
+	if !limit.Multiple() {
+		return nil
+	}
+	args := []string{limit.LimitParameter("limit")}
+	if limit.Offset > 0 {
+		args = append(args, limit.OffsetParameter("limit"))
+	}
+	return args
+}
+
+// GenerateQuery 在仓库接口的包中生成 List 和 Count 使用的查询条件 Query, 每个仓库接口包只生成一次
+func (l Layout) GenerateQuery(arg types.RunArg) error {
+	l.templates = TemplateFS
+	return l.GenerateFile(l.Filename(arg.RepoOutput, "query"), "query.go.tpl", TempData{RepoPackageName: PackageName(arg.RepoOutput)})
+}
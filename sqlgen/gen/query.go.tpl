@@ -0,0 +1,206 @@
+package {{$.RepoPackageName}}
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+// Query is the query option of List and Count, it has the same methods as
+// gormx.Query of the gorm repos so that the repos of all backends are queried
+// in the same way, the adapters translate it into the statement of their
+// database library. The conditions are joined by AND in the order they are
+// added, except the ones added by Or.
+type Query struct {
+    clauses QueryClauses
+}
+
+// QueryClauses are the clauses of a Query read by the adapters.
+type QueryClauses struct {
+    Where   []Condition
+    Columns []string
+    Group   []string
+    Order   []Order
+    Limit   int
+    Offset  int
+}
+
+// Condition is a condition of the where clause.
+type Condition struct {
+    // Column is the column of the condition, it is empty for the raw
+    // expression added by Or.
+    Column string
+    // Operator is one of =, <>, IN, NOT IN, >, >=, <, <=, LIKE, BETWEEN,
+    // IS NULL and IS NOT NULL, it is the raw expression with ? placeholders
+    // if Column is empty.
+    Operator string
+    Args     []interface{}
+    // Or joins the condition by OR instead of AND.
+    Or bool
+}
+
+// Order is an item of the order by clause.
+type Order struct {
+    // Column is the column to sort by, it is empty for the raw expression
+    // added by OrderBy.
+    Column string
+    Desc   bool
+    // Expr is the raw expression such as "id DESC".
+    Expr string
+}
+
+// Page is the paging parameter of Query.Page, the order by is a json object
+// like {"id":"descend"}.
+type Page interface {
+    GetOrderBy() string
+    GetPage() uint64
+    GetPageSize() uint64
+}
+
+// NewQuery returns an empty query option that matches all rows.
+func NewQuery() *Query {
+    return &Query{}
+}
+
+// Clauses returns the clauses of the query, a nil query has no clauses.
+func (q *Query) Clauses() QueryClauses {
+    if q == nil {
+        return QueryClauses{}
+    }
+    return q.clauses
+}
+
+func (q *Query) where(column, operator string, args ...interface{}) *Query {
+    q.clauses.Where = append(q.clauses.Where, Condition{Column: column, Operator: operator, Args: args})
+    return q
+}
+
+// Eq adds the condition key = value.
+func (q *Query) Eq(key string, value interface{}) *Query {
+    return q.where(key, "=", value)
+}
+
+// Not adds the condition key <> value.
+func (q *Query) Not(key string, value interface{}) *Query {
+    return q.where(key, "<>", value)
+}
+
+// In adds the condition key IN (value), value is a slice.
+func (q *Query) In(key string, value interface{}) *Query {
+    return q.where(key, "IN", value)
+}
+
+// NotIn adds the condition key NOT IN (value), value is a slice.
+func (q *Query) NotIn(key string, value interface{}) *Query {
+    return q.where(key, "NOT IN", value)
+}
+
+// Gt adds the condition key > value.
+func (q *Query) Gt(key string, value interface{}) *Query {
+    return q.where(key, ">", value)
+}
+
+// Gte adds the condition key >= value.
+func (q *Query) Gte(key string, value interface{}) *Query {
+    return q.where(key, ">=", value)
+}
+
+// Lt adds the condition key < value.
+func (q *Query) Lt(key string, value interface{}) *Query {
+    return q.where(key, "<", value)
+}
+
+// Lte adds the condition key <= value.
+func (q *Query) Lte(key string, value interface{}) *Query {
+    return q.where(key, "<=", value)
+}
+
+// Like adds the condition key LIKE %value%.
+func (q *Query) Like(key string, value interface{}) *Query {
+    return q.where(key, "LIKE", fmt.Sprintf("%%%v%%", value))
+}
+
+// Between adds the condition key BETWEEN lower AND upper.
+func (q *Query) Between(key string, lower, upper interface{}) *Query {
+    return q.where(key, "BETWEEN", lower, upper)
+}
+
+// IsNull adds the condition key IS NULL.
+func (q *Query) IsNull(key string) *Query {
+    return q.where(key, "IS NULL")
+}
+
+// NotNull adds the condition key IS NOT NULL.
+func (q *Query) NotNull(key string) *Query {
+    return q.where(key, "IS NOT NULL")
+}
+
+// Or adds the condition query joined by OR, such as Or("name = ?", "foo").
+func (q *Query) Or(query string, args ...interface{}) *Query {
+    q.clauses.Where = append(q.clauses.Where, Condition{Operator: query, Args: args, Or: true})
+    return q
+}
+
+// Select selects the columns instead of all columns.
+func (q *Query) Select(columns ...string) *Query {
+    q.clauses.Columns = append(q.clauses.Columns, columns...)
+    return q
+}
+
+// Group adds the group by columns.
+func (q *Query) Group(columns ...string) *Query {
+    q.clauses.Group = append(q.clauses.Group, columns...)
+    return q
+}
+
+// OrderBy adds the order by expression, such as "id DESC".
+func (q *Query) OrderBy(order string) *Query {
+    q.clauses.Order = append(q.clauses.Order, Order{Expr: order})
+    return q
+}
+
+// Limit limits the number of rows, Count ignores it.
+func (q *Query) Limit(limit int) *Query {
+    q.clauses.Limit = limit
+    return q
+}
+
+// Offset skips the rows, Count ignores it.
+func (q *Query) Offset(offset int) *Query {
+    q.clauses.Offset = offset
+    return q
+}
+
+// Page adds the limit, offset and order by of page, the page number starts
+// from 1, the order by descend|ascend is converted to DESC|ASC.
+func (q *Query) Page(page Page) *Query {
+    if page == nil {
+        return q
+    }
+
+    pageNum, pageSize, orderBy := page.GetPage(), page.GetPageSize(), page.GetOrderBy()
+    if pageNum > 0 {
+        q = q.Offset(int((pageNum - 1) * pageSize))
+    }
+    if pageSize > 0 {
+        q = q.Limit(int(pageSize))
+    }
+
+    if orderBy != "" {
+        orderByMap := make(map[string]string)
+        if err := json.Unmarshal([]byte(orderBy), &orderByMap); err != nil {
+            return q
+        }
+        for field, order := range orderByMap {
+            order = strings.ToUpper(strings.TrimSuffix(order, "end"))
+            if order != "ASC" && order != "DESC" {
+                return q
+            }
+            // only one field is supported.
+            q.clauses.Order = append(q.clauses.Order, Order{Column: field, Desc: order == "DESC"})
+            return q
+        }
+    }
+
+    return q
+}
@@ -0,0 +1,76 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+func TestQuery(t *testing.T) {
+	dxl, err := parser.Parse("CREATE TABLE `order` (id bigint NOT NULL primary key, name varchar(64) NOT NULL);\n" +
+		"-- fn: ListByIds\nselect * from `order` where id in (?);\n" +
+		"-- fn: CountByName\nselect name, count(id) AS c from `order` where id > ? group by name having c > ? order by name desc;")
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	// where、group by、having、order by 和查询列中的标识符都按方言加引号
+	for dialect, want := range map[string][]string{
+		types.DialectMySQL: {
+			"\"SELECT `id`, `name` FROM `order` WHERE `id` IN (?)\"",
+			"\"SELECT `name`, count(`id`) AS `c` FROM `order` WHERE `id` > ? GROUP BY `name` HAVING `c` > ? ORDER BY `name` desc\"",
+		},
+		types.DialectPostgres: {
+			`"SELECT \"id\", \"name\" FROM \"order\" WHERE \"id\" IN (?)"`,
+			`"SELECT \"name\", count(\"id\") AS \"c\" FROM \"order\" WHERE \"id\" > ? GROUP BY \"name\" HAVING \"c\" > ? ORDER BY \"name\" desc"`,
+		},
+		types.DialectSQLite: {
+			`"SELECT \"id\", \"name\" FROM \"order\" WHERE \"id\" IN (?)"`,
+			`"SELECT \"name\", count(\"id\") AS \"c\" FROM \"order\" WHERE \"id\" > ? GROUP BY \"name\" HAVING \"c\" > ? ORDER BY \"name\" desc"`,
+		},
+	} {
+		for i, stmt := range ctx[0].SelectStmt {
+			query, err := Query(stmt, dialect)
+			assert.NoError(t, err)
+			assert.Equal(t, want[i], query, dialect)
+		}
+	}
+}
+
+func TestTableQueryFuncMap(t *testing.T) {
+	dxl, err := parser.Parse("CREATE TABLE `order` (tenant_id bigint NOT NULL, id bigint NOT NULL, name varchar(64) NOT NULL, version bigint NOT NULL, PRIMARY KEY (tenant_id, id));")
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+	version, err := NewVersion(ctx[0].Table, types.RunArg{})
+	assert.NoError(t, err)
+
+	funcMap := TableQueryFuncMap(TempData{Context: ctx[0], Version: version, Dialect: types.DialectPostgres})
+	assert.Equal(t, []UpdateColumn{{Field: "Name", Set: `"\"name\" = ?"`}}, funcMap["UpdateColumns"].(func() []UpdateColumn)())
+	assert.Equal(t, `"\"version\" = \"version\" + 1"`, funcMap["VersionSetSQL"].(func() string)())
+	assert.Equal(t, `"UPDATE \"order\" SET " + strings.Join(sets, ", ") + " WHERE \"tenant_id\" = ? AND \"id\" = ? AND \"version\" = ?"`,
+		funcMap["UpdateSQL"].(func(string) string)("sets"))
+	assert.Equal(t, `"DELETE FROM \"order\" WHERE \"tenant_id\" = ? AND \"id\" = ?"`, funcMap["DeleteByIDSQL"].(func() string)())
+	assert.Equal(t, `"\"order\""`, funcMap["QuotedTable"].(func() string)())
+	assert.Equal(t, `"\"tenant_id\", \"id\", \"name\", \"version\""`, funcMap["QuotedColumns"].(func() string)())
+	assert.Contains(t, funcMap, "Query")
+}
+
+func TestWrapInClauseArgs(t *testing.T) {
+	dxl, err := parser.Parse(`CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(255) NOT NULL);
+-- fn: FindByNames
+select * from foo where name in (?) and id > ?;`)
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	args, err := WrapInClauseArgs(ctx[0].SelectStmt[0].Where, "where", "bun.In")
+	assert.NoError(t, err)
+	assert.Equal(t, "bun.In(where.NameIn), where.IdGT", args)
+	args, err = WrapInArgs(ctx[0].SelectStmt[0], "po", "SQLIn")
+	assert.NoError(t, err)
+	assert.Equal(t, "SQLIn(where.NameIn), where.IdGT", args)
+}
@@ -0,0 +1,207 @@
+package gen
+
+import (
+	"io/fs"
+	"text/template"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// TempData 是模版数据, 自定义模版可以依赖其中的字段, 字段只增不改
+type TempData struct {
+	// Context 包含表结构 (.Table), sql 注释生成的语句 (.InsertStmt, .SelectStmt, .UpdateStmt, .DeleteStmt, .Transaction)
+	// 和外键生成的关联 (.BelongsTo, .HasMany)
+	spec.Context
+	// AdapterPackageName 适配器的包名
+	AdapterPackageName string
+	// RepoPackage 仓库接口的完整包名
+	RepoPackage string
+	// RepoPackageName 仓库接口的包名
+	RepoPackageName string
+	// EntityPackage 实体的完整包名
+	EntityPackage string
+	// AutoAudit 是否开启自动审计
+	AutoAudit bool
+	// Audit 表中存在的审计列, 未开启自动审计时为空
+	Audit Audit
+	// Version 表中用于乐观锁的版本列, 不存在时为空
+	Version Version
+	// Split 是否为分离布局
+	Split bool
+	// Dialect 数据库的方言: mysql, postgres, sqlite
+	Dialect string
+}
+
+// builtinMethods 是各个后端的适配器都有的内置方法, sql 注释中的函数名不能与之重复
+var builtinMethods = []string{
+	"DB",
+	"GetByID",
+	"Create",
+	"Update",
+	"Delete",
+	"GetByKey",
+	"UpdateByKey",
+	"DeleteByKey",
+	"IsDuplicatedKeyError",
+	"IsNotFoundError",
+}
+
+// QueryMethods 是按查询条件列出和计数的方法, 提供这两个方法的后端 (gorm, bun, sqlx) 需要将其加入 Table.Methods
+var QueryMethods = []string{"List", "Count"}
+
+// Backend 是 orm 后端, 后端只提供模版和模版函数, 生成的流程由 Run 统一处理.
+// 模版以后端的名称为前缀, 如 sqlx_adapter.go.tpl、sqlx_repo.go.tpl、sqlx_entity.go.tpl.
+type Backend struct {
+	// Name 是后端的名称
+	Name string
+	// Templates 是后端内置的模版
+	Templates fs.FS
+	// FuncMaps 是后端专用的模版函数
+	FuncMaps []template.FuncMap
+	// Helpers 是每个适配器包只生成一次的辅助文件, 如 sqlx_tx 使用 sqlx_tx.go.tpl 生成 sqlx_tx.go
+	Helpers []string
+	// Query 是否在仓库接口包中生成 List 和 Count 使用的查询条件 Query, 由适配器的辅助函数转换为语句,
+	// gorm 直接使用 gormx.Query
+	Query bool
+	// Mock 是否支持 MockTypes 参数, 使用 <name>_sqlite_mock.go.tpl 和 <name>_docker_mysql_mock.go.tpl 生成 mock 适配器
+	Mock bool
+	// Table 返回表专用的模版数据和模版函数, 可以为空
+	Table func(td TempData) (Table, error)
+}
+
+// Table 是后端为每张表提供的模版数据和模版函数
+type Table struct {
+	// Data 是模版数据, 为空时使用 TempData
+	Data interface{}
+	// FuncMap 是表专用的模版函数
+	FuncMap template.FuncMap
+	// Methods 是后端根据表额外生成的方法, 同样不能与 sql 注释中的函数名重复
+	Methods []string
+}
+
+// Run 使用后端的模版为每张表生成适配器、仓库接口和实体,
+// 然后生成每个包只需要一份的辅助文件、查询条件、审计和版本冲突错误.
+func Run(list []spec.Context, arg types.RunArg, b Backend) error {
+	layout := NewLayout(arg, b.Templates)
+	dialect := arg.Dialect
+	if dialect == "" {
+		dialect = types.DialectMySQL
+	}
+	var hasVersion bool
+	for _, ctx := range list {
+		audit, err := NewAudit(ctx.Table, arg)
+		if err != nil {
+			return err
+		}
+		version, err := NewVersion(ctx.Table, arg)
+		if err != nil {
+			return err
+		}
+		hasVersion = hasVersion || version.IsValid()
+
+		td := TempData{
+			Context:            ctx,
+			AdapterPackageName: PackageName(arg.Output),
+			RepoPackage:        arg.RepoPackage,
+			RepoPackageName:    PackageName(arg.RepoOutput),
+			EntityPackage:      arg.EntityPackage,
+			AutoAudit:          arg.AutoAudit,
+			Audit:              audit,
+			Version:            version,
+			Split:              layout.Split(),
+			Dialect:            dialect,
+		}
+		var table Table
+		if b.Table != nil {
+			if table, err = b.Table(td); err != nil {
+				return err
+			}
+		}
+		var data interface{} = td
+		if table.Data != nil {
+			data = table.Data
+		}
+
+		methods := map[string]struct{}{}
+		for _, v := range append(append([]string(nil), builtinMethods...), table.Methods...) {
+			methods[v] = struct{}{}
+		}
+		if err := CheckFuncName(ctx, methods); err != nil {
+			return err
+		}
+
+		funcMaps := append([]template.FuncMap{FuncMap, TableFuncMap(ctx)}, b.FuncMaps...)
+		funcMaps = append(funcMaps, table.FuncMap)
+		for _, f := range b.files(layout, arg, ctx.Table.Name) {
+			if err := layout.GenerateFile(f.filename, f.template, data, funcMaps...); err != nil {
+				return err
+			}
+		}
+		if err := layout.Scaffold(arg.Output, arg.RepoOutput, ctx.Table.Name, data); err != nil {
+			return err
+		}
+	}
+
+	if len(list) == 0 {
+		return nil
+	}
+
+	// 辅助函数, 每个适配器包只生成一次
+	for _, name := range b.Helpers {
+		if err := layout.GenerateFile(layout.Filename(arg.Output, name), name+".go.tpl", TempData{
+			AdapterPackageName: PackageName(arg.Output),
+			RepoPackage:        arg.RepoPackage,
+			Dialect:            dialect,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 查询条件, 每个仓库接口包只生成一次
+	if b.Query {
+		if err := layout.GenerateQuery(arg); err != nil {
+			return err
+		}
+	}
+
+	// 获取操作人的函数, 每个适配器包只生成一次
+	if err := layout.GenerateAudit(arg); err != nil {
+		return err
+	}
+
+	// 版本冲突的错误, 每个仓库接口包只生成一次
+	if hasVersion {
+		if err := layout.GenerateVersion(arg); err != nil {
+			return err
+		}
+	}
+	return layout.Err()
+}
+
+// file 是使用模版生成的文件
+type file struct {
+	filename string
+	template string
+}
+
+// files 返回为表生成的文件: 适配器、仓库接口、实体以及 MockTypes 参数对应的 mock 适配器
+func (b Backend) files(layout Layout, arg types.RunArg, table string) []file {
+	list := []file{
+		{layout.AdapterFilename(arg.Output, table), b.Name + "_adapter.go.tpl"},
+		{layout.Filename(arg.RepoOutput, table+"_repo"), b.Name + "_repo.go.tpl"},
+		{layout.Filename(arg.EntityOutput, table+"_entity"), b.Name + "_entity.go.tpl"},
+	}
+	if !b.Mock {
+		return list
+	}
+	for _, mockType := range arg.MockTypes {
+		switch mockType {
+		case types.MockDocker:
+			list = append(list, file{layout.Filename(arg.Output, table+"_docker_mock_adapter"), b.Name + "_docker_mysql_mock.go.tpl"})
+		case types.MockSQLite:
+			list = append(list, file{layout.Filename(arg.Output, table+"_sqlite_mock_adapter"), b.Name + "_sqlite_mock.go.tpl"})
+		}
+	}
+	return list
+}
@@ -0,0 +1,4 @@
+package {{$.AdapterPackageName}}
+
+// This file is created once by codegen and will never be overwritten,
+// add the custom methods of {{UpperCamel $.Table.Name}}Adapter here.
@@ -0,0 +1,7 @@
+package {{$.RepoPackageName}}
+
+// {{UpperCamel $.Table.Name}}RepoExtension declares the custom methods of {{UpperCamel $.Table.Name}}Repo,
+// implement them on {{UpperCamel $.Table.Name}}Adapter in the adapter package.
+//
+// This file is created once by codegen and will never be overwritten.
+type {{UpperCamel $.Table.Name}}RepoExtension interface{}
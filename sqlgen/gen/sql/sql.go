@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"embed"
+	"fmt"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/xyzbit/codegen/sqlgen/gen"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// TemplateFS 是内置的模版, 可以通过 templates_dir 覆盖同名的模版
+//
+//go:embed *.tpl
+var TemplateFS embed.FS
+
+var funcMap = template.FuncMap{
+	"HasIn":      gen.HasIn,
+	"ExpandArgs": expandArgs,
+	"ScanArgs":   scanArgs,
+}
+
+func Run(list []spec.Context, arg types.RunArg) error {
+	return gen.Run(list, arg, gen.Backend{
+		Name:      "sql",
+		Templates: TemplateFS,
+		FuncMaps:  []template.FuncMap{funcMap},
+		Helpers:   []string{"sql_db"},
+		Table: func(td gen.TempData) (gen.Table, error) {
+			return gen.Table{FuncMap: gen.TableQueryFuncMap(td)}, nil
+		},
+	})
+}
+
+// expandArgs 返回传给 SQLExpandIn 的参数, in 和 not in 的参数使用 SQLIn 包装.
+func expandArgs(dml spec.DML) (string, error) {
+	return gen.WrapInArgs(dml, "po", "SQLIn")
+}
+
+// scanArgs 按列的顺序返回 Scan 的参数.
+func scanArgs(columns spec.Columns, receiver string) []string {
+	var list []string
+	for _, c := range columns {
+		list = append(list, fmt.Sprintf("&%s.%s", receiver, strcase.ToCamel(c.Name)))
+	}
+	return list
+}
@@ -0,0 +1,442 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "strings"
+
+    {{if eq $.Dialect "mysql"}}"github.com/go-sql-driver/mysql"{{end}}
+    {{- if $.Table.HasConvertedColumn}}
+    "github.com/samber/lo"
+    {{- end}}
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
+
+    repo "{{$.RepoPackage}}"
+    entity "{{$.EntityPackage}}"
+)
+
+// {{UpperCamel $.Table.Name}}Adapter represents a {{$.Table.Name}} adapter.
+type {{UpperCamel $.Table.Name}}Adapter struct {
+    db    *sql.DB
+    stmts *SQLStmtCache
+}
+
+// New{{UpperCamel $.Table.Name}}Repo returns a new {{$.Table.Name}} adapter implemented {{$.Table.Name}}Repo,
+// stmts is the prepared statement cache of db shared by the adapters, the
+// caller closes it after the adapters are no longer used.
+func New{{UpperCamel $.Table.Name}}Repo (
+    db *sql.DB,
+    stmts *SQLStmtCache,
+) repo.{{UpperCamel $.Table.Name}}Repo {
+    return &{{UpperCamel $.Table.Name}}Adapter{db: db, stmts: stmts}
+}
+
+// conn returns the transaction in ctx if it exists, otherwise returns the db.
+func (m *{{UpperCamel $.Table.Name}}Adapter) conn(ctx context.Context) SQLConn {
+    if tx := SQLTxFromContext(ctx); tx != nil {
+        return tx
+    }
+    return m.db
+}
+
+// Create creates  {{$.Table.Name}} data.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*entity.{{UpperCamel $.Table.Name}}) error {
+    if len(es)==0{
+        return fmt.Errorf("data is empty")
+    }
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.CreateVariables}}
+    {{- end}}
+
+    return SQLTransaction(ctx, m.db, func(txCtx context.Context) error {
+        stmt, release, err := m.stmts.Prepare(txCtx, {{InsertSQL}})
+        if err != nil {
+            return err
+        }
+        defer release()
+        for _, e := range es {
+            po := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+            {{- if $.Audit.IsValid}}
+            {{$.Audit.OnCreate "po"}}
+            {{- end}}
+            if _, err := stmt.ExecContext(txCtx {{- range $.Table.Columns}}{{if not .AutoIncrement}}, po.{{UpperCamel .Name}}{{end}}{{end}}); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// GetByKey get {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    stmt, release, err := m.stmts.Prepare(ctx, {{GetByIDSQL}})
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+
+    var po {{UpperCamel $.Table.Name}}
+    if err := stmt.QueryRowContext(ctx{{range $.Table.PrimaryColumnList}}, key.{{UpperCamel .Name}}{{end}}).Scan({{template "scan" (ScanArgs $.Table.Columns "po")}}); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- else -}}
+// GetByID get {{$.Table.Name}} by id.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    stmt, release, err := m.stmts.Prepare(ctx, {{GetByIDSQL}})
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+
+    var po {{UpperCamel $.Table.Name}}
+    if err := stmt.QueryRowContext(ctx, id).Scan({{template "scan" (ScanArgs $.Table.Columns "po")}}); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// UpdateByKey update {{$.Table.Name}} by primary key, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) UpdateByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error {
+    po := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- range $.Table.PrimaryColumnList}}
+    po.{{UpperCamel .Name}} = key.{{UpperCamel .Name}}
+    {{- end}}
+    {{- template "updateByPrimaryKey" $}}
+}
+{{- else -}}
+// Update update {{$.Table.Name}}, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
+    po := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- template "updateByPrimaryKey" $}}
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// DeleteByKey delete {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    stmt, release, err := m.stmts.Prepare(ctx, {{DeleteByIDSQL}})
+    if err != nil {
+        return err
+    }
+    defer release()
+    _, err = stmt.ExecContext(ctx{{range $.Table.PrimaryColumnList}}, key.{{UpperCamel .Name}}{{end}})
+    return err
+}
+{{- else -}}
+// Delete delete {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id {{PrimaryKeyType}}) error {
+    stmt, release, err := m.stmts.Prepare(ctx, {{DeleteByIDSQL}})
+    if err != nil {
+        return err
+    }
+    defer release()
+    _, err = stmt.ExecContext(ctx, id)
+    return err
+}
+{{- end}}
+
+// IsDuplicatedKeyError use to check error is unique key conflict error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
+    {{- if eq $.Dialect "postgres"}}
+    // the errors of github.com/lib/pq and github.com/jackc/pgx both implement SQLState.
+    var pgErr interface{ SQLState() string }
+    return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+    {{- else if eq $.Dialect "sqlite"}}
+    // github.com/mattn/go-sqlite3 and modernc.org/sqlite report the same message.
+    return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+    {{- else}}
+    var mysqlErr *mysql.MySQLError
+    return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+    {{- end}}
+}
+
+// IsNotFoundError use to check error is record not found error,
+// the single row queries return sql.ErrNoRows if no rows are found.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
+    return errors.Is(err, sql.ErrNoRows)
+}
+
+{{- range $stmt := $.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $.UpdateStmt}}
+{{template "exec" $stmt}}
+{{end -}}
+{{- range $stmt := $.DeleteStmt}}
+{{template "exec" $stmt}}
+{{end -}}
+{{- range $tx := $.Transaction}}
+// {{UpperCamel $tx.FuncName}} is generated from sql:
+// {{LineComment $tx.SQL}}
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg repo.{{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*repo.{{$tx.ResultStructureName}}, {{end}}error) {
+    {{- if $tx.HasResult}}
+    var result repo.{{$tx.ResultStructureName}}
+    {{- end}}
+    // join the outer transaction if it exists.
+    err := SQLTransaction(ctx, m.db, func(txCtx context.Context) error {
+        var err error
+        {{- range $v := $tx.Statements}}
+        {{- if IsInsert $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data...); err != nil {
+            return err
+        }
+        {{- else if IsSelect $v}}
+        if result.{{UpperCamel $v.FuncName}}, err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Having.IsValid}}, arg.{{UpperCamel $v.FuncName}}Having{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsUpdate $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsDelete $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- end}}
+        {{- end}}
+        return nil
+    })
+    {{- if $tx.HasResult}}
+    if err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    return err
+    {{- end}}
+}
+{{range $stmt := $tx.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.UpdateStmt}}
+{{template "exec" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.DeleteStmt}}
+{{template "exec" $stmt}}
+{{end -}}
+{{end}}
+
+// {{UpperCamel $.Table.Name}} represents a {{$.Table.Name}} struct data.
+type {{UpperCamel $.Table.Name}} struct { {{range $.Table.Columns}}
+    {{UpperCamel .Name}} {{.POGoType}} `json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+}
+
+func to{{UpperCamel $.Table.Name}}PO(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) *{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToPO "e"}},
+        {{- end}}
+    }
+}
+
+func to{{UpperCamel $.Table.Name}}Entity(ctx context.Context, po *{{UpperCamel $.Table.Name}}) *entity.{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &entity.{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToEntity "po"}},
+        {{- end}}
+    }
+}
+
+{{define "scan"}}{{range $i, $v := .}}{{if $i}}, {{end}}{{$v}}{{end}}{{end}}
+{{define "insert"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $stmt.TableInfo.Name}}) error {
+    if len(data) == 0 {
+        return fmt.Errorf("data is empty")
+    }
+
+    return SQLTransaction(ctx, m.db, func(txCtx context.Context) error {
+        stmt, release, err := m.stmts.Prepare(txCtx, {{Query $stmt}})
+        if err != nil {
+            return err
+        }
+        defer release()
+        for _, v := range data {
+            po := to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, v)
+            if _, err := stmt.ExecContext(txCtx, {{Args $stmt "po"}}); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+{{end}}
+{{define "select"}}
+{{- $stmt := .}}
+{{- $extra := IsExtraResult $stmt.ReceiverName}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having repo.{{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if $extra}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error) {
+    {{- if $stmt.Limit.One}}
+    {{- if HasIn $stmt}}
+    query, args := SQLExpandIn({{Query $stmt}}, {{ExpandArgs $stmt}})
+    row := m.conn(ctx).QueryRowContext(ctx, query, args...)
+    {{- else}}
+    stmt, release, err := m.stmts.Prepare(ctx, {{Query $stmt}})
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+    row := stmt.QueryRowContext(ctx{{with Args $stmt "po"}}, {{.}}{{end}})
+    {{- end}}
+    {{- if $extra}}
+
+    var result repo.{{$stmt.ReceiverName}}
+    if err := row.Scan({{template "scan" (ScanArgs $stmt.ColumnInfo "result")}}); err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    var po {{UpperCamel $stmt.FromInfo.Name}}
+    if err := row.Scan({{template "scan" (ScanArgs $stmt.ColumnInfo "po")}}); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po), nil
+    {{- end}}
+    {{- else}}
+    {{- if HasIn $stmt}}
+    query, args := SQLExpandIn({{Query $stmt}}, {{ExpandArgs $stmt}})
+    rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+    {{- else}}
+    stmt, release, err := m.stmts.Prepare(ctx, {{Query $stmt}})
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+    rows, err := stmt.QueryContext(ctx{{with Args $stmt "po"}}, {{.}}{{end}})
+    {{- end}}
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var result []*{{if $extra}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}
+    for rows.Next() {
+        {{- if $extra}}
+        var v repo.{{$stmt.ReceiverName}}
+        if err := rows.Scan({{template "scan" (ScanArgs $stmt.ColumnInfo "v")}}); err != nil {
+            return nil, err
+        }
+        result = append(result, &v)
+        {{- else}}
+        var po {{UpperCamel $stmt.FromInfo.Name}}
+        if err := rows.Scan({{template "scan" (ScanArgs $stmt.ColumnInfo "po")}}); err != nil {
+            return nil, err
+        }
+        result = append(result, to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po))
+        {{- end}}
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    return result, nil
+    {{- end}}
+}
+
+{{end}}
+{{define "exec"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableName}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if IsUpdate $stmt}}, data *entity.{{UpperCamel $stmt.TableName}}{{end}}{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    {{- if IsUpdate $stmt}}
+    po := to{{UpperCamel $stmt.TableName}}PO(ctx, data)
+    {{- end}}
+    {{- if HasIn $stmt}}
+    query, args := SQLExpandIn({{Query $stmt}}, {{ExpandArgs $stmt}})
+    _, err := m.conn(ctx).ExecContext(ctx, query, args...)
+    return err
+    {{- else}}
+    stmt, release, err := m.stmts.Prepare(ctx, {{Query $stmt}})
+    if err != nil {
+        return err
+    }
+    defer release()
+    _, err = stmt.ExecContext(ctx{{with Args $stmt "po"}}, {{.}}{{end}})
+    return err
+    {{- end}}
+}
+
+{{end}}
+{{define "updateByPrimaryKey"}}
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "po"}}
+    {{- end}}
+
+    // like Updates of gorm, only the non-zero fields are updated, the
+    // statement is not prepared since the updated columns change.
+    var (
+        sets []string
+        args []interface{}
+    )
+    {{- range UpdateColumns}}
+    if !SQLIsZero(po.{{.Field}}) {
+        sets = append(sets, {{.Set}})
+        args = append(args, po.{{.Field}})
+    }
+    {{- end}}
+    {{- if $.Version.IsValid}}
+    sets = append(sets, {{VersionSetSQL}})
+    {{- else}}
+    if len(sets) == 0 {
+        return nil
+    }
+    {{- end}}
+    args = append(args{{range $.Table.PrimaryColumnList}}, po.{{UpperCamel .Name}}{{end}}{{if $.Version.IsValid}}, po.{{$.Version.Field}}{{end}})
+    query := SQLRebind({{UpdateSQL "sets"}})
+    {{- if $.Version.IsValid}}
+
+    result, err := m.conn(ctx).ExecContext(ctx, query, args...)
+    if err != nil {
+        return err
+    }
+    {{- template "version" $}}
+    {{- else}}
+
+    _, err := m.conn(ctx).ExecContext(ctx, query, args...)
+    return err
+    {{- end}}
+{{- end}}
+{{define "versionComment"}}
+{{- if $.Version.IsValid}}, {{$.Version.Column}} is increased and repo.ErrVersionConflict is returned if it is out of date{{end}}
+{{- end}}
+{{define "version"}}
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}}++
+
+    return nil
+{{- end}}
@@ -0,0 +1,289 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "container/list"
+    "context"
+    "database/sql"
+    "reflect"
+    {{- if eq $.Dialect "postgres"}}
+    "strconv"
+    {{- end}}
+    "strings"
+    "sync"
+)
+
+// SQLConn is the common interface of *sql.DB and *sql.Tx.
+type SQLConn interface {
+    ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+    QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+    QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type sqlTxKey struct{}
+
+// NewSQLTxContext returns a new context carrying the transaction,
+// the adapters called with the context run in the transaction.
+func NewSQLTxContext(ctx context.Context, tx *sql.Tx) context.Context {
+    return context.WithValue(ctx, sqlTxKey{}, tx)
+}
+
+// SQLTxFromContext returns the transaction in ctx, it returns nil if not exists.
+func SQLTxFromContext(ctx context.Context) *sql.Tx {
+    tx, _ := ctx.Value(sqlTxKey{}).(*sql.Tx)
+    return tx
+}
+
+// SQLTransaction runs fn in a transaction, it joins the outer transaction if
+// ctx already carries one, otherwise it begins a new transaction and commits
+// it if fn returns nil.
+func SQLTransaction(ctx context.Context, db *sql.DB, fn func(txCtx context.Context) error) (err error) {
+    if SQLTxFromContext(ctx) != nil {
+        return fn(ctx)
+    }
+
+    tx, err := db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer func() {
+        if p := recover(); p != nil {
+            _ = tx.Rollback()
+            panic(p)
+        }
+        if err != nil {
+            _ = tx.Rollback()
+        }
+    }()
+
+    if err = fn(NewSQLTxContext(ctx, tx)); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// DefaultSQLStmtCacheSize is the number of the cached statements if the size
+// passed to NewSQLStmtCache is not positive.
+const DefaultSQLStmtCacheSize = 256
+
+// SQLStmtCache caches the prepared statements of a db so that each query is
+// prepared only once, the least recently used statement is closed when the
+// number of the statements exceeds the size. It is safe for concurrent use,
+// the adapters of the same db share one cache.
+type SQLStmtCache struct {
+    db    *sql.DB
+    size  int
+    mu    sync.Mutex
+    lru   *list.List // the front is the most recently used *sqlStmt.
+    stmts map[string]*list.Element
+}
+
+// sqlStmt is a cached statement, the evicted statement is closed after all
+// the callers released it.
+type sqlStmt struct {
+    query   string
+    stmt    *sql.Stmt
+    refs    int
+    evicted bool
+}
+
+// NewSQLStmtCache returns a new prepared statement cache of db which holds
+// size statements at most, the cache should be closed before closing db.
+func NewSQLStmtCache(db *sql.DB, size int) *SQLStmtCache {
+    if size <= 0 {
+        size = DefaultSQLStmtCacheSize
+    }
+    return &SQLStmtCache{db: db, size: size, lru: list.New(), stmts: make(map[string]*list.Element)}
+}
+
+// Prepare returns the prepared statement of query and the function to release
+// it, the statement must not be used after released. The ? placeholders are
+// replaced by SQLRebind, the statement is bound to the transaction in ctx if
+// it exists.
+func (c *SQLStmtCache) Prepare(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+    s, err := c.get(ctx, query)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    release := func() {
+        c.mu.Lock()
+        defer c.mu.Unlock()
+        s.refs--
+        _ = c.closeEvicted(s)
+    }
+    if tx := SQLTxFromContext(ctx); tx != nil {
+        return tx.StmtContext(ctx, s.stmt), release, nil
+    }
+    return s.stmt, release, nil
+}
+
+// get returns the cached statement of query with its references increased.
+// If not cached, it prepares the query without holding the lock, so that the
+// other queries are not blocked by the round trip, then caches it and evicts
+// the least recently used statements. The statement of the caller losing the
+// race to cache the same query is closed.
+func (c *SQLStmtCache) get(ctx context.Context, query string) (*sqlStmt, error) {
+    c.mu.Lock()
+    if s := c.lookup(query); s != nil {
+        s.refs++
+        c.mu.Unlock()
+        return s, nil
+    }
+    c.mu.Unlock()
+
+    stmt, err := c.db.PrepareContext(ctx, SQLRebind(query))
+    if err != nil {
+        return nil, err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if s := c.lookup(query); s != nil {
+        // another caller cached the query first.
+        _ = stmt.Close()
+        s.refs++
+        return s, nil
+    }
+    s := &sqlStmt{query: query, stmt: stmt, refs: 1}
+    c.stmts[query] = c.lru.PushFront(s)
+    for c.lru.Len() > c.size {
+        _ = c.evict(c.lru.Back())
+    }
+    return s, nil
+}
+
+// lookup returns the cached statement of query and marks it as the most
+// recently used, it returns nil if not cached.
+func (c *SQLStmtCache) lookup(query string) *sqlStmt {
+    e, ok := c.stmts[query]
+    if !ok {
+        return nil
+    }
+    c.lru.MoveToFront(e)
+    return e.Value.(*sqlStmt)
+}
+
+// evict removes the statement from the cache and closes it if not in use.
+func (c *SQLStmtCache) evict(e *list.Element) error {
+    s := c.lru.Remove(e).(*sqlStmt)
+    delete(c.stmts, s.query)
+    s.evicted = true
+    return c.closeEvicted(s)
+}
+
+// closeEvicted closes the evicted statement which is not in use.
+func (c *SQLStmtCache) closeEvicted(s *sqlStmt) error {
+    if s.evicted && s.refs == 0 {
+        return s.stmt.Close()
+    }
+    return nil
+}
+
+// Len returns the number of the cached statements.
+func (c *SQLStmtCache) Len() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.lru.Len()
+}
+
+// Close closes all the prepared statements, the statements in use are closed
+// after released.
+func (c *SQLStmtCache) Close() error {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    var err error
+    for c.lru.Len() > 0 {
+        if e := c.evict(c.lru.Back()); e != nil && err == nil {
+            err = e
+        }
+    }
+    return err
+}
+
+// SQLIsZero reports whether v is the zero value of its type, Update skips
+// the zero value fields like Updates of gorm.
+func SQLIsZero(v interface{}) bool {
+    return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// sqlInArg is the argument of the IN expression.
+type sqlInArg []interface{}
+
+// SQLIn wraps the argument of the IN expression, it is expanded by SQLExpandIn.
+func SQLIn[T any](values []T) interface{} {
+    arg := make(sqlInArg, 0, len(values))
+    for _, v := range values {
+        arg = append(arg, v)
+    }
+    return arg
+}
+
+// SQLExpandIn expands the placeholders of the arguments wrapped by SQLIn and
+// replaces the ? placeholders like SQLRebind, the queries with IN expressions
+// are not prepared since the number of placeholders changes with the arguments.
+func SQLExpandIn(query string, args ...interface{}) (string, []interface{}) {
+    var (
+        b       strings.Builder
+        newArgs = make([]interface{}, 0, len(args))
+        i       int
+    )
+    for _, r := range query {
+        if r != '?' || i >= len(args) {
+            b.WriteRune(r)
+            continue
+        }
+
+        in, ok := args[i].(sqlInArg)
+        if !ok {
+            newArgs = append(newArgs, args[i])
+            b.WriteString(sqlBindVar(len(newArgs)))
+            i++
+            continue
+        }
+        if len(in) == 0 {
+            // an empty IN list matches nothing.
+            b.WriteString("NULL")
+        }
+        for j, v := range in {
+            if j > 0 {
+                b.WriteString(", ")
+            }
+            newArgs = append(newArgs, v)
+            b.WriteString(sqlBindVar(len(newArgs)))
+        }
+        i++
+    }
+    return b.String(), newArgs
+}
+
+// SQLRebind replaces the ? placeholders of query with the bind variables of
+// the {{$.Dialect}} database.
+func SQLRebind(query string) string {
+    {{- if eq $.Dialect "postgres"}}
+    var (
+        b strings.Builder
+        n int
+    )
+    for _, r := range query {
+        if r != '?' {
+            b.WriteRune(r)
+            continue
+        }
+        n++
+        b.WriteString(sqlBindVar(n))
+    }
+    return b.String()
+    {{- else}}
+    return query
+    {{- end}}
+}
+
+// sqlBindVar returns the bind variable of the n-th argument, n starts from 1.
+func sqlBindVar(n int) string {
+    {{- if eq $.Dialect "postgres"}}
+    return "$" + strconv.Itoa(n)
+    {{- else}}
+    return "?"
+    {{- end}}
+}
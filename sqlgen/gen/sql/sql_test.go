@@ -0,0 +1,296 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/gen/gentest"
+	"github.com/xyzbit/codegen/sqlgen/gen/testdata"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// require 是生成的代码依赖的模块
+var require = []string{
+	"github.com/go-sql-driver/mysql v1.8.1",
+	"github.com/samber/lo v1.49.1",
+}
+
+// sqliteRequire 是使用 sqlite 内存数据库运行生成的代码时依赖的模块
+var sqliteRequire = append(require, "github.com/mattn/go-sqlite3 v1.14.22")
+
+// findOneTest 使用 sqlite 内存数据库运行带 having 条件的查询, mysql 方言生成的查询 sqlite 同样支持
+const findOneTest = `package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestFindOne(t *testing.T) {
+	ctx := context.Background()
+	// the statements are prepared on the db while Create holds a connection in a
+	// transaction, so the connections share the memory database.
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE foo (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NULL UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+
+	stmts := NewSQLStmtCache(db, 0)
+	defer stmts.Close()
+	r := NewFooRepo(db, stmts)
+	if err := r.Create(ctx, &entity.Foo{Name: "foo"}, &entity.Foo{Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 1, Valid: true}})
+	if err != nil || result.C.Int64 != 2 {
+		t.Fatalf("FindOne: %v, %v", result, err)
+	}
+	if _, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 2, Valid: true}}); !r.IsNotFoundError(err) {
+		t.Fatalf("FindOne having: %v", err)
+	}
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, testdata.TestSql), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/find_one_test.go", findOneTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestRunBuild(t *testing.T) {
+	for name, layout := range map[string]string{"default": "", "split": types.LayoutSplit} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			arg := gentest.RunArg(dir)
+			arg.Layout = layout
+			arg.AutoAudit = true
+			err := Run(gentest.Context(t, testdata.BuildSql), arg)
+			assert.NoError(t, err)
+			gentest.Vet(t, dir, require...)
+		})
+	}
+}
+
+// postgresTest 检查 postgres 方言的占位符和唯一键冲突错误, lib/pq 和 pgx 的错误都实现了 SQLState
+const postgresTest = `package data
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSQLRebind(t *testing.T) {
+	query := SQLRebind(` + "`" + `UPDATE "order" SET "email" = ? WHERE "id" = ?` + "`" + `)
+	if query != ` + "`" + `UPDATE "order" SET "email" = $1 WHERE "id" = $2` + "`" + ` {
+		t.Fatal(query)
+	}
+
+	query, args := SQLExpandIn(` + "`" + `SELECT id FROM "order" WHERE id IN (?) AND email = ?` + "`" + `, SQLIn([]int64{1, 2}), "foo")
+	if query != ` + "`" + `SELECT id FROM "order" WHERE id IN ($1, $2) AND email = $3` + "`" + ` || len(args) != 3 {
+		t.Fatal(query, args)
+	}
+}
+
+type pgError string
+
+func (e pgError) Error() string    { return string(e) }
+func (e pgError) SQLState() string { return string(e) }
+
+func TestIsDuplicatedKeyError(t *testing.T) {
+	r := &OrderAdapter{}
+	if !r.IsDuplicatedKeyError(fmt.Errorf("create: %w", pgError("23505"))) {
+		t.Fatal("unique violation")
+	}
+	if r.IsDuplicatedKeyError(pgError("23503")) || r.IsDuplicatedKeyError(errors.New("23505")) {
+		t.Fatal("not unique violation")
+	}
+}
+`
+
+func TestRunPostgres(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Dialect = types.DialectPostgres
+	err := Run(gentest.DialectContext(t, types.DialectPostgres, `CREATE TABLE "order" (id bigserial PRIMARY KEY, email varchar(64) NOT NULL);`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/postgres_test.go", postgresTest)
+	gentest.Test(t, dir, require...)
+}
+
+// sqliteTest 使用 sqlite 内存数据库运行生成的适配器
+const sqliteTest = `package data
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestOrder(t *testing.T) {
+	ctx := context.Background()
+	// the statements are prepared on the db while Create holds a connection in a
+	// transaction, so the connections share the memory database.
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(` + "`" + `CREATE TABLE "order" (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL DEFAULT '')` + "`" + `); err != nil {
+		t.Fatal(err)
+	}
+
+	stmts := NewSQLStmtCache(db, 0)
+	defer stmts.Close()
+	r := NewOrderRepo(db, stmts)
+	if err := r.Create(ctx, &entity.Order{Email: "foo@example.com", Name: "foo"}, &entity.Order{Email: "bar@example.com", Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 1)
+	if err != nil || e.Email != "foo@example.com" {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+	e, err = r.GetByEmail(ctx, service.GetByEmailWhereParameter{EmailEqual: "bar@example.com"})
+	if err != nil || e.Id != 2 {
+		t.Fatalf("GetByEmail: %v, %v", e, err)
+	}
+	list, err := r.ListByIds(ctx, service.ListByIdsWhereParameter{IdIn: []int64{1, 2}})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListByIds: %v, %v", list, err)
+	}
+	if err := r.Create(ctx, &entity.Order{Email: "foo@example.com"}); !r.IsDuplicatedKeyError(err) {
+		t.Fatalf("Create duplicated: %v", err)
+	}
+	// 与 gorm 的 Updates 一样, 零值字段不更新
+	if err := r.Update(ctx, &entity.Order{Id: 2, Name: "bar2"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err = r.GetByID(ctx, 2)
+	if err != nil || e.Name != "bar2" || e.Email != "bar@example.com" {
+		t.Fatalf("Update: %v, %v", e, err)
+	}
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 1); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID deleted: %v", err)
+	}
+}
+
+func TestSQLStmtCache(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	stmts := NewSQLStmtCache(db, 1)
+
+	foo, release, err := stmts.Prepare(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the statement in use is closed after released.
+	_, releaseBar, err := stmts.Prepare(ctx, "SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseBar()
+	if stmts.Len() != 1 {
+		t.Fatalf("Len: %d", stmts.Len())
+	}
+	if _, err := foo.ExecContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if _, err := foo.ExecContext(ctx); err == nil {
+		t.Fatal("the evicted statement is not closed")
+	}
+
+	// the concurrent callers preparing the same query share one statement.
+	var (
+		wg   sync.WaitGroup
+		list = make([]*sql.Stmt, 8)
+	)
+	for i := range list {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stmt, release, err := stmts.Prepare(ctx, "SELECT 3")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			list[i] = stmt
+		}(i)
+	}
+	wg.Wait()
+	for _, v := range list {
+		if v == nil || v != list[0] {
+			t.Fatal("the statements of the same query are not shared")
+		}
+	}
+	if _, err := list[0].ExecContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stmts.Close(); err != nil || stmts.Len() != 0 {
+		t.Fatalf("Close: %v, %d", err, stmts.Len())
+	}
+}
+`
+
+func TestRunSQLite(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Dialect = types.DialectSQLite
+	err := Run(gentest.DialectContext(t, types.DialectSQLite, testdata.SQLiteSql), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/sqlite_test.go", sqliteTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestExpandArgs(t *testing.T) {
+	dxl, err := parser.Parse(`CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(255) NOT NULL);
+-- fn: FindByNames
+select * from foo where name in (?) and id > ?;`)
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	args, err := expandArgs(ctx[0].SelectStmt[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "SQLIn(where.NameIn), where.IdGT", args)
+}
+
+func TestRunConflictFuncName(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: GetByID
+select * from foo where id = ? limit 1;`), gentest.RunArg(dir))
+	assert.Error(t, err)
+}
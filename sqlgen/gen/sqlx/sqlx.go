@@ -0,0 +1,26 @@
+package sqlx
+
+import (
+	"embed"
+
+	"github.com/xyzbit/codegen/sqlgen/gen"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// TemplateFS 是内置的模版, 可以通过 templates_dir 覆盖同名的模版
+//
+//go:embed *.tpl
+var TemplateFS embed.FS
+
+func Run(list []spec.Context, arg types.RunArg) error {
+	return gen.Run(list, arg, gen.Backend{
+		Name:      "sqlx",
+		Templates: TemplateFS,
+		Helpers:   []string{"sqlx_tx", "sqlx_query"},
+		Query:     true,
+		Table: func(td gen.TempData) (gen.Table, error) {
+			return gen.Table{FuncMap: gen.TableQueryFuncMap(td), Methods: gen.QueryMethods}, nil
+		},
+	})
+}
@@ -0,0 +1,435 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "strings"
+
+    {{if eq $.Dialect "mysql"}}"github.com/go-sql-driver/mysql"{{end}}
+    "github.com/jmoiron/sqlx"
+    "github.com/samber/lo"
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
+
+    repo "{{$.RepoPackage}}"
+    entity "{{$.EntityPackage}}"
+)
+
+// {{UpperCamel $.Table.Name}}Adapter represents a {{$.Table.Name}} adapter.
+type {{UpperCamel $.Table.Name}}Adapter struct {
+    db *sqlx.DB
+}
+
+// New{{UpperCamel $.Table.Name}}Repo returns a new {{$.Table.Name}} adapter implemented {{$.Table.Name}}Repo.
+func New{{UpperCamel $.Table.Name}}Repo (
+    db *sqlx.DB,
+) repo.{{UpperCamel $.Table.Name}}Repo {
+    return &{{UpperCamel $.Table.Name}}Adapter{db: db}
+}
+
+// DB returns the transaction in ctx if it exists, otherwise returns the db.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DB(ctx context.Context) sqlx.ExtContext {
+    if tx := SqlxTxFromContext(ctx); tx != nil {
+        return tx
+    }
+    return m.db
+}
+
+// Create creates  {{$.Table.Name}} data.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*entity.{{UpperCamel $.Table.Name}}) error {
+    if len(es)==0{
+        return fmt.Errorf("data is empty")
+    }
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.CreateVariables}}
+    {{- end}}
+
+    pos := lo.Map(es, func(v *entity.{{UpperCamel $.Table.Name}}, _ int) *{{UpperCamel $.Table.Name}} {
+        {{- if $.Audit.IsValid}}
+        p := to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{$.Audit.OnCreate "p"}}
+        return p
+        {{- else}}
+        return to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{- end}}
+    })
+
+    _, err := sqlx.NamedExecContext(ctx, m.DB(ctx), {{NamedInsertSQL}}, pos)
+    return err
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// GetByKey get {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    query := m.DB(ctx).Rebind({{GetByIDSQL}})
+    if err := sqlx.GetContext(ctx, m.DB(ctx), &po, query{{range $.Table.PrimaryColumnList}}, key.{{UpperCamel .Name}}{{end}}); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- else -}}
+// GetByID get {{$.Table.Name}} by id.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    query := m.DB(ctx).Rebind({{GetByIDSQL}})
+    if err := sqlx.GetContext(ctx, m.DB(ctx), &po, query, id); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- end}}
+
+// List list {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) List(ctx context.Context, query *repo.Query) ([]*entity.{{UpperCamel $.Table.Name}}, error) {
+    var pos []*{{UpperCamel $.Table.Name}}
+
+    db := m.DB(ctx)
+    q, args, err := SqlxQuery(db, query, {{QuotedTable}}, {{QuotedColumns}})
+    if err != nil {
+        return nil, err
+    }
+    if err := sqlx.SelectContext(ctx, db, &pos, q, args...); err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $.Table.Name}}, _ int) *entity.{{UpperCamel $.Table.Name}} {
+        return to{{UpperCamel $.Table.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+}
+
+// Count count {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Count(ctx context.Context, query *repo.Query) (int64, error) {
+    var count int64
+
+    db := m.DB(ctx)
+    q, args, err := SqlxCountQuery(db, query, {{QuotedTable}})
+    if err != nil {
+        return 0, err
+    }
+    err = sqlx.GetContext(ctx, db, &count, q, args...)
+
+    return count, err
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// UpdateByKey update {{$.Table.Name}} by primary key, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) UpdateByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- range $.Table.PrimaryColumnList}}
+    p.{{UpperCamel .Name}} = key.{{UpperCamel .Name}}
+    {{- end}}
+    {{- template "updateByPrimaryKey" $}}
+}
+{{- else -}}
+// Update update {{$.Table.Name}}, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- template "updateByPrimaryKey" $}}
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// DeleteByKey delete {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    query := m.DB(ctx).Rebind({{DeleteByIDSQL}})
+    _, err := m.DB(ctx).ExecContext(ctx, query{{range $.Table.PrimaryColumnList}}, key.{{UpperCamel .Name}}{{end}})
+    return err
+}
+{{- else -}}
+// Delete delete {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id {{PrimaryKeyType}}) error {
+    query := m.DB(ctx).Rebind({{DeleteByIDSQL}})
+    _, err := m.DB(ctx).ExecContext(ctx, query, id)
+    return err
+}
+{{- end}}
+
+// IsDuplicatedKeyError use to check error is unique key conflict error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
+    {{- if eq $.Dialect "postgres"}}
+    // the errors of github.com/lib/pq and github.com/jackc/pgx both implement SQLState.
+    var pgErr interface{ SQLState() string }
+    return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+    {{- else if eq $.Dialect "sqlite"}}
+    // github.com/mattn/go-sqlite3 and modernc.org/sqlite report the same message.
+    return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+    {{- else}}
+    var mysqlErr *mysql.MySQLError
+    return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+    {{- end}}
+}
+
+// IsNotFoundError use to check error is record not found error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
+    return errors.Is(err, sql.ErrNoRows)
+}
+
+{{- range $stmt := $.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{- range $tx := $.Transaction}}
+// {{UpperCamel $tx.FuncName}} is generated from sql:
+// {{LineComment $tx.SQL}}
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg repo.{{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*repo.{{$tx.ResultStructureName}}, {{end}}error) {
+    {{- if $tx.HasResult}}
+    var result repo.{{$tx.ResultStructureName}}
+    {{- end}}
+    // join the outer transaction if it exists.
+    err := SqlxTransaction(ctx, m.db, func(txCtx context.Context) error {
+        var err error
+        {{- range $v := $tx.Statements}}
+        {{- if IsInsert $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data...); err != nil {
+            return err
+        }
+        {{- else if IsSelect $v}}
+        if result.{{UpperCamel $v.FuncName}}, err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Having.IsValid}}, arg.{{UpperCamel $v.FuncName}}Having{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsUpdate $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsDelete $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- end}}
+        {{- end}}
+        return nil
+    })
+    {{- if $tx.HasResult}}
+    if err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    return err
+    {{- end}}
+}
+{{range $stmt := $tx.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{end}}
+
+// {{UpperCamel $.Table.Name}} represents a {{$.Table.Name}} struct data.
+type {{UpperCamel $.Table.Name}} struct { {{range $.Table.Columns}}
+    {{UpperCamel .Name}} {{.POGoType}} `db:"{{.Name}}" json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+}
+
+func to{{UpperCamel $.Table.Name}}PO(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) *{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToPO "e"}},
+        {{- end}}
+    }
+}
+
+func to{{UpperCamel $.Table.Name}}Entity(ctx context.Context, po *{{UpperCamel $.Table.Name}}) *entity.{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &entity.{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToEntity "po"}},
+        {{- end}}
+    }
+}
+
+{{define "insert"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $stmt.TableInfo.Name}}) error {
+    if len(data) == 0 {
+        return fmt.Errorf("data is empty")
+    }
+
+    query := m.DB(ctx).Rebind({{Query $stmt}})
+    for _, v := range data {
+        po := to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, v)
+        if _, err := m.DB(ctx).ExecContext(ctx, query, {{Args $stmt "po"}}); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+{{end}}
+{{define "select"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having repo.{{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if IsExtraResult $stmt.ReceiverName}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error) {
+    {{- template "query" $stmt}}
+    {{- if IsExtraResult $stmt.ReceiverName}}
+    {{- if $stmt.Limit.One}}
+
+    var result repo.{{$stmt.ReceiverName}}
+    if err := sqlx.GetContext(ctx, m.DB(ctx), &result, query, args...); err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    var result []*repo.{{$stmt.ReceiverName}}
+    if err := sqlx.SelectContext(ctx, m.DB(ctx), &result, query, args...); err != nil {
+        return nil, err
+    }
+
+    return result, nil
+    {{- end}}
+    {{- else}}
+    {{- if $stmt.Limit.One}}
+
+    var po {{UpperCamel $stmt.FromInfo.Name}}
+    if err := sqlx.GetContext(ctx, m.DB(ctx), &po, query, args...); err != nil {
+        return nil, err
+    }
+
+    return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po), nil
+    {{- else}}
+
+    var pos []*{{UpperCamel $stmt.FromInfo.Name}}
+    if err := sqlx.SelectContext(ctx, m.DB(ctx), &pos, query, args...); err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $stmt.FromInfo.Name}}, _ int) *entity.{{UpperCamel $stmt.FromInfo.Name}} {
+        return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+    {{- end}}
+    {{- end}}
+}
+
+{{end}}
+{{define "update"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data *entity.{{UpperCamel $stmt.TableInfo.Name}}{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    po := to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, data)
+    {{- template "query" $stmt}}
+
+    if _, err := m.DB(ctx).ExecContext(ctx, query, args...); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+{{end}}
+{{define "delete"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    {{- template "query" $stmt}}
+
+    if _, err := m.DB(ctx).ExecContext(ctx, query, args...); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+{{end}}
+{{define "query"}}
+{{- $stmt := .}}
+{{- $args := Args $stmt "po"}}
+    query := {{Query $stmt}}
+    args := []interface{}{ {{- $args -}} }
+    {{- if $args}}
+    query, args, err := sqlx.In(query, args...)
+    if err != nil {
+        return {{if IsSelect $stmt}}nil, {{end}}err
+    }
+    {{- end}}
+    query = m.DB(ctx).Rebind(query)
+{{- end}}
+{{define "updateByPrimaryKey"}}
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+
+    // like Updates of gorm, only the non-zero fields are updated.
+    var (
+        sets []string
+        args []interface{}
+    )
+    {{- range UpdateColumns}}
+    if !SqlxIsZero(p.{{.Field}}) {
+        sets = append(sets, {{.Set}})
+        args = append(args, p.{{.Field}})
+    }
+    {{- end}}
+    {{- if $.Version.IsValid}}
+    sets = append(sets, {{VersionSetSQL}})
+    {{- else}}
+    if len(sets) == 0 {
+        return nil
+    }
+    {{- end}}
+    args = append(args{{range $.Table.PrimaryColumnList}}, p.{{UpperCamel .Name}}{{end}}{{if $.Version.IsValid}}, p.{{$.Version.Field}}{{end}})
+    query := m.DB(ctx).Rebind({{UpdateSQL "sets"}})
+    {{- if $.Version.IsValid}}
+
+    result, err := m.DB(ctx).ExecContext(ctx, query, args...)
+    if err != nil {
+        return err
+    }
+    {{- template "version" $}}
+    {{- else}}
+
+    _, err := m.DB(ctx).ExecContext(ctx, query, args...)
+    return err
+    {{- end}}
+{{- end}}
+{{define "versionComment"}}
+{{- if $.Version.IsValid}}, {{$.Version.Column}} is increased and repo.ErrVersionConflict is returned if it is out of date{{end}}
+{{- end}}
+{{define "version"}}
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}}++
+
+    return nil
+{{- end}}
@@ -0,0 +1,150 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "fmt"
+    "reflect"
+    "strconv"
+    "strings"
+
+    "github.com/jmoiron/sqlx"
+
+    repo "{{$.RepoPackage}}"
+)
+
+// SqlxQuery returns the select statement of table with the clauses of query
+// and its arguments, columns is the select list if query selects no columns.
+// The arguments of IN are expanded and the placeholders are rebound for db,
+// a nil query matches all rows.
+func SqlxQuery(db sqlx.ExtContext, query *repo.Query, table, columns string) (string, []interface{}, error) {
+    clauses := query.Clauses()
+    if len(clauses.Columns) > 0 {
+        columns = sqlxQuoteList(clauses.Columns)
+    }
+
+    var b strings.Builder
+    fmt.Fprintf(&b, "SELECT %s FROM %s", columns, table)
+    args := sqlxWhere(&b, clauses)
+    var orders []string
+    for _, v := range clauses.Order {
+        switch {
+        case v.Column == "":
+            orders = append(orders, v.Expr)
+        case v.Desc:
+            orders = append(orders, sqlxQuote(v.Column)+" DESC")
+        default:
+            orders = append(orders, sqlxQuote(v.Column)+" ASC")
+        }
+    }
+    if len(orders) > 0 {
+        b.WriteString(" ORDER BY " + strings.Join(orders, ", "))
+    }
+    if clauses.Limit > 0 {
+        b.WriteString(" LIMIT " + strconv.Itoa(clauses.Limit))
+    }
+    if clauses.Offset > 0 {
+        {{- if eq $.Dialect "mysql"}}
+        if clauses.Limit <= 0 {
+            // mysql requires a limit before the offset.
+            b.WriteString(" LIMIT 18446744073709551615")
+        }
+        {{- else if eq $.Dialect "sqlite"}}
+        if clauses.Limit <= 0 {
+            // sqlite requires a limit before the offset.
+            b.WriteString(" LIMIT -1")
+        }
+        {{- end}}
+        b.WriteString(" OFFSET " + strconv.Itoa(clauses.Offset))
+    }
+    return sqlxBind(db, b.String(), args)
+}
+
+// SqlxCountQuery returns the count statement of table with the conditions of
+// query and its arguments, the order by, limit and offset are ignored.
+func SqlxCountQuery(db sqlx.ExtContext, query *repo.Query, table string) (string, []interface{}, error) {
+    clauses := query.Clauses()
+
+    var (
+        b    strings.Builder
+        args []interface{}
+    )
+    if len(clauses.Group) > 0 {
+        // count the groups.
+        fmt.Fprintf(&b, "SELECT count(*) FROM (SELECT 1 FROM %s", table)
+        args = sqlxWhere(&b, clauses)
+        b.WriteString(") t")
+    } else {
+        fmt.Fprintf(&b, "SELECT count(*) FROM %s", table)
+        args = sqlxWhere(&b, clauses)
+    }
+    return sqlxBind(db, b.String(), args)
+}
+
+// sqlxWhere writes the where and group by clauses and returns the arguments.
+func sqlxWhere(b *strings.Builder, clauses repo.QueryClauses) []interface{} {
+    var args []interface{}
+    for i, v := range clauses.Where {
+        switch {
+        case i == 0:
+            b.WriteString(" WHERE ")
+        case v.Or:
+            b.WriteString(" OR ")
+        default:
+            b.WriteString(" AND ")
+        }
+        switch v.Operator {
+        case "IN", "NOT IN":
+            b.WriteString(sqlxQuote(v.Column) + " " + v.Operator + " (?)")
+        case "BETWEEN":
+            b.WriteString(sqlxQuote(v.Column) + " BETWEEN ? AND ?")
+        case "IS NULL", "IS NOT NULL":
+            b.WriteString(sqlxQuote(v.Column) + " " + v.Operator)
+        default:
+            if v.Column == "" {
+                b.WriteString(v.Operator)
+            } else {
+                b.WriteString(sqlxQuote(v.Column) + " " + v.Operator + " ?")
+            }
+        }
+        args = append(args, v.Args...)
+    }
+    if len(clauses.Group) > 0 {
+        b.WriteString(" GROUP BY " + sqlxQuoteList(clauses.Group))
+    }
+    return args
+}
+
+// sqlxBind expands the arguments of IN and rebinds the placeholders for db.
+func sqlxBind(db sqlx.ExtContext, query string, args []interface{}) (string, []interface{}, error) {
+    query, args, err := sqlx.In(query, args...)
+    if err != nil {
+        return "", nil, err
+    }
+    return db.Rebind(query), args, nil
+}
+
+func sqlxQuoteList(names []string) string {
+    list := make([]string, 0, len(names))
+    for _, v := range names {
+        list = append(list, sqlxQuote(v))
+    }
+    return strings.Join(list, ", ")
+}
+
+// sqlxQuote quotes the identifier for the database, such as order.id.
+func sqlxQuote(name string) string {
+    parts := strings.Split(name, ".")
+    for i, v := range parts {
+        {{- if eq $.Dialect "mysql"}}
+        parts[i] = "`" + strings.ReplaceAll(v, "`", "``") + "`"
+        {{- else}}
+        parts[i] = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+        {{- end}}
+    }
+    return strings.Join(parts, ".")
+}
+
+// SqlxIsZero reports whether v is the zero value of its type, Update skips
+// the zero value fields like Updates of gorm.
+func SqlxIsZero(v interface{}) bool {
+    return v == nil || reflect.ValueOf(v).IsZero()
+}
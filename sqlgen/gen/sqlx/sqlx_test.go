@@ -0,0 +1,578 @@
+package sqlx
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/gen/gentest"
+	"github.com/xyzbit/codegen/sqlgen/gen/testdata"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// require 是生成的代码依赖的模块
+var require = []string{
+	"github.com/go-sql-driver/mysql v1.8.1",
+	"github.com/jmoiron/sqlx v1.4.0",
+	"github.com/samber/lo v1.49.1",
+}
+
+// sqliteRequire 是使用 sqlite 内存数据库运行生成的代码时依赖的模块
+var sqliteRequire = append(require, "github.com/mattn/go-sqlite3 v1.14.22")
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, testdata.TestSql), gentest.RunArg(dir))
+	assert.NoError(t, err)
+	gentest.Vet(t, dir, require...)
+}
+
+func TestRunBuild(t *testing.T) {
+	for name, layout := range map[string]string{"default": "", "split": types.LayoutSplit} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			arg := gentest.RunArg(dir)
+			arg.Layout = layout
+			arg.AutoAudit = true
+			err := Run(gentest.Context(t, testdata.BuildSql), arg)
+			assert.NoError(t, err)
+			gentest.Vet(t, dir, require...)
+		})
+	}
+}
+
+// postgresTest 检查 postgres 方言的唯一键冲突错误, lib/pq 和 pgx 的错误都实现了 SQLState
+const postgresTest = `package data
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type pgError string
+
+func (e pgError) Error() string    { return string(e) }
+func (e pgError) SQLState() string { return string(e) }
+
+func TestIsDuplicatedKeyError(t *testing.T) {
+	r := &OrderAdapter{}
+	if !r.IsDuplicatedKeyError(fmt.Errorf("create: %w", pgError("23505"))) {
+		t.Fatal("unique violation")
+	}
+	if r.IsDuplicatedKeyError(pgError("23503")) || r.IsDuplicatedKeyError(errors.New("23505")) {
+		t.Fatal("not unique violation")
+	}
+}
+`
+
+func TestRunPostgres(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Dialect = types.DialectPostgres
+	err := Run(gentest.DialectContext(t, types.DialectPostgres, `CREATE TABLE "order" (id bigserial PRIMARY KEY, email varchar(64) NOT NULL);`), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/postgres_test.go", postgresTest)
+	gentest.Test(t, dir, require...)
+}
+
+// sqliteTest 使用 sqlite 内存数据库运行生成的适配器
+const sqliteTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestOrder(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(` + "`" + `CREATE TABLE "order" (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL DEFAULT '')` + "`" + `)
+
+	r := NewOrderRepo(db)
+	if err := r.Create(ctx, &entity.Order{Email: "foo@example.com", Name: "foo"}, &entity.Order{Email: "bar@example.com", Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 1)
+	if err != nil || e.Email != "foo@example.com" {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+	e, err = r.GetByEmail(ctx, service.GetByEmailWhereParameter{EmailEqual: "bar@example.com"})
+	if err != nil || e.Id != 2 {
+		t.Fatalf("GetByEmail: %v, %v", e, err)
+	}
+	list, err := r.ListByIds(ctx, service.ListByIdsWhereParameter{IdIn: []int64{1, 2}})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListByIds: %v, %v", list, err)
+	}
+	if err := r.Create(ctx, &entity.Order{Email: "foo@example.com"}); !r.IsDuplicatedKeyError(err) {
+		t.Fatalf("Create duplicated: %v", err)
+	}
+	if err := r.Create(ctx, &entity.Order{Email: "baz@example.com", Name: "baz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err = r.List(ctx, service.NewQuery().Like("email", "ba").OrderBy("id"))
+	if err != nil || len(list) != 2 || list[0].Name != "bar" || list[1].Name != "baz" {
+		t.Fatalf("List: %v, %v", list, err)
+	}
+	list, err = r.List(ctx, service.NewQuery().In("id", []int64{1, 3}).Not("name", "baz"))
+	if err != nil || len(list) != 1 || list[0].Name != "foo" {
+		t.Fatalf("List in: %v, %v", list, err)
+	}
+	list, err = r.List(ctx, service.NewQuery().Page(page{}))
+	if err != nil || len(list) != 2 || list[0].Id != 3 {
+		t.Fatalf("List page: %v, %v", list, err)
+	}
+	list, err = r.List(ctx, service.NewQuery().Select("id").OrderBy("id").Offset(2))
+	if err != nil || len(list) != 1 || list[0].Id != 3 || list[0].Email != "" {
+		t.Fatalf("List offset: %v, %v", list, err)
+	}
+	count, err := r.Count(ctx, service.NewQuery().Gt("id", 1).Limit(1))
+	if err != nil || count != 2 {
+		t.Fatalf("Count: %d, %v", count, err)
+	}
+	count, err = r.Count(ctx, service.NewQuery().Group("name"))
+	if err != nil || count != 3 {
+		t.Fatalf("Count group: %d, %v", count, err)
+	}
+
+	// 与 gorm 的 Updates 一样, 零值字段不更新
+	if err := r.Update(ctx, &entity.Order{Id: 2, Name: "bar2"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err = r.GetByID(ctx, 2)
+	if err != nil || e.Name != "bar2" || e.Email != "bar@example.com" {
+		t.Fatalf("Update: %v, %v", e, err)
+	}
+
+	// DB 与 List/Count 一样加入 ctx 中的事务
+	err = SqlxTransaction(ctx, db, func(txCtx context.Context) error {
+		if _, err := r.DB(txCtx).ExecContext(txCtx, "DELETE FROM \"order\" WHERE id = ?", 1); err != nil {
+			return err
+		}
+		count, err := r.Count(txCtx, nil)
+		if err != nil || count != 2 {
+			t.Fatalf("Count in tx: %d, %v", count, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 1); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByID deleted: %v", err)
+	}
+}
+
+type page struct{}
+
+func (page) GetOrderBy() string { return ` + "`" + `{"id":"descend"}` + "`" + ` }
+func (page) GetPage() uint64 { return 1 }
+func (page) GetPageSize() uint64 { return 2 }
+`
+
+func TestRunSQLite(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Dialect = types.DialectSQLite
+	err := Run(gentest.DialectContext(t, types.DialectSQLite, testdata.SQLiteSql), arg)
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/sqlite_test.go", sqliteTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestRunConflictFuncName(t *testing.T) {
+	for _, fn := range []string{"GetByID", "Count"} {
+		dir := t.TempDir()
+		err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: `+fn+`
+select * from foo where id = ? limit 1;`), gentest.RunArg(dir))
+		assert.Error(t, err, fn)
+	}
+}
+
+// nullableTest 使用 sqlite 内存数据库运行可空列为指针的适配器, mysql 方言生成的查询 sqlite 同样支持
+const nullableTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestNullable(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE foo (id INTEGER PRIMARY KEY AUTOINCREMENT, uid INTEGER NULL, name TEXT NULL)")
+
+	r := NewFooRepo(db)
+	uid, name := int64(1), "foo"
+	if err := r.Create(ctx, &entity.Foo{Uid: &uid, Name: &name}, &entity.Foo{}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 2)
+	if err != nil || e.Uid != nil || e.Name != nil {
+		t.Fatalf("GetByID null: %v, %v", e, err)
+	}
+	list, err := r.FindByUid(ctx, service.FindByUidWhereParameter{UidEqual: &uid, NameIn: []string{name}})
+	if err != nil || len(list) != 1 || *list[0].Uid != uid || *list[0].Name != name {
+		t.Fatalf("FindByUid: %v, %v", list, err)
+	}
+}
+`
+
+func TestRunNullable(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.NullableContext(t, `CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, uid bigint DEFAULT NULL, name varchar(255) DEFAULT NULL, PRIMARY KEY (id));
+-- fn: FindByUid
+select * from foo where uid = ? and name in (?);`, spec.NullPointer), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/nullable_test.go", nullableTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+// compositePrimaryKeyTest 使用 sqlite 内存数据库运行复合主键的适配器
+const compositePrimaryKeyTest = `package data
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestCompositePrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE foo (tenant_id INTEGER NOT NULL, order_id TEXT NOT NULL, name TEXT NOT NULL DEFAULT '', PRIMARY KEY (tenant_id, order_id))")
+
+	r := NewFooRepo(db)
+	key := service.FooKey{TenantId: 1, OrderId: "o1"}
+	if err := r.Create(ctx, &entity.Foo{TenantId: 1, OrderId: "o1", Name: "foo"}, &entity.Foo{TenantId: 2, OrderId: "o1", Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByKey(ctx, key)
+	if err != nil || e.Name != "foo" {
+		t.Fatalf("GetByKey: %v, %v", e, err)
+	}
+	if err := r.UpdateByKey(ctx, key, &entity.Foo{Name: "foo2"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err = r.GetByKey(ctx, key)
+	if err != nil || e.Name != "foo2" {
+		t.Fatalf("UpdateByKey: %v, %v", e, err)
+	}
+	if err := r.DeleteByKey(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByKey(ctx, key); !r.IsNotFoundError(err) {
+		t.Fatalf("GetByKey deleted: %v", err)
+	}
+	e, err = r.GetByKey(ctx, service.FooKey{TenantId: 2, OrderId: "o1"})
+	if err != nil || e.Name != "bar" {
+		t.Fatalf("GetByKey other: %v, %v", e, err)
+	}
+
+	if _, ok := reflect.TypeOf((*service.FooRepo)(nil)).Elem().MethodByName("GetByID"); ok {
+		t.Fatal("GetByID is generated with the composite primary key")
+	}
+}
+`
+
+func TestRunCompositePrimaryKey(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, "CREATE TABLE foo (tenant_id bigint NOT NULL, order_id varchar(64) NOT NULL, name varchar(255) NOT NULL DEFAULT '', PRIMARY KEY (tenant_id, order_id));"), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/composite_test.go", compositePrimaryKeyTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+// autoAuditTest 使用 sqlite 内存数据库运行自动填充审计字段的适配器
+const autoAuditTest = `package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+)
+
+func TestAutoAudit(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, creator TEXT NOT NULL, operator TEXT NOT NULL, created_time DATETIME NOT NULL)")
+
+	r := NewFooRepo(db)
+	OperatorFromContext = func(ctx context.Context) string { return "lee" }
+	if err := r.Create(ctx, &entity.Foo{Id: 1, Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	created, err := r.GetByID(ctx, 1)
+	if err != nil || created.Creator != "lee" || created.Operator != "lee" || created.CreatedTime.IsZero() {
+		t.Fatalf("Create: %v, %v", created, err)
+	}
+
+	// the creator and created time are not updated.
+	OperatorFromContext = func(ctx context.Context) string { return "bob" }
+	if err := r.Update(ctx, &entity.Foo{Id: 1, Name: "bar", Creator: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 1)
+	if err != nil || e.Name != "bar" || e.Creator != "lee" || e.Operator != "bob" || !e.CreatedTime.Equal(created.CreatedTime) {
+		t.Fatalf("Update: %v, %v", e, err)
+	}
+}
+`
+
+func TestRunAutoAudit(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.AutoAudit = true
+	err := Run(gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(64) NOT NULL, creator varchar(64) NOT NULL, operator varchar(64) NOT NULL, created_time datetime NOT NULL);"), arg)
+	assert.NoError(t, err)
+
+	// the persistent object is separated from the last method by a blank line
+	adapter, err := os.ReadFile(filepath.Join(dir, "data", "foo_adpter.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(adapter), "}\n\n// Foo represents a foo struct data.")
+
+	gentest.WriteFile(t, dir, "data/audit_test.go", autoAuditTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+// optimisticLockTest 使用 sqlite 内存数据库运行乐观锁的适配器
+const optimisticLockTest = `package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestOptimisticLock(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, version INTEGER NOT NULL DEFAULT 0)")
+	db.MustExec("CREATE TABLE bar (id INTEGER PRIMARY KEY, version TEXT NOT NULL)")
+
+	r := NewFooRepo(db)
+	if err := r.Create(ctx, &entity.Foo{Id: 1, Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := r.GetByID(ctx, 1)
+	stale, _ := r.GetByID(ctx, 1)
+	e.Name = "bar"
+	if err := r.Update(ctx, e); err != nil || e.Version != 1 {
+		t.Fatalf("Update: %v, %v", e, err)
+	}
+	stale.Name = "baz"
+	if err := r.Update(ctx, stale); !errors.Is(err, service.ErrVersionConflict) {
+		t.Fatalf("Update stale: %v", err)
+	}
+	e, _ = r.GetByID(ctx, 1)
+	if e.Name != "bar" || e.Version != 1 {
+		t.Fatalf("GetByID: %v", e)
+	}
+
+	// the version which is not an integer is an ordinary column
+	bar := NewBarRepo(db)
+	if err := bar.Create(ctx, &entity.Bar{Id: 1, Version: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bar.Update(ctx, &entity.Bar{Id: 1, Version: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bar.Update(ctx, &entity.Bar{Id: 1, Version: "c"}); err != nil {
+		t.Fatalf("Update ordinary version: %v", err)
+	}
+}
+`
+
+func TestRunOptimisticLock(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(64) NOT NULL, version int NOT NULL DEFAULT 0);
+CREATE TABLE bar (id bigint NOT NULL primary key, version varchar(32) NOT NULL);`), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/optimistic_lock_test.go", optimisticLockTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+// money 是类型覆盖使用的自定义类型所在的包
+const money = `package money
+
+type Money struct {
+	Cents int64
+}
+
+func ToCents(m Money) int64 {
+	return m.Cents
+}
+
+func FromCents(cents int64) Money {
+	return Money{Cents: cents}
+}
+`
+
+// typeOverridesTest 使用 sqlite 内存数据库运行类型覆盖的适配器, 查询参数同样按转换函数转换
+const typeOverridesTest = `package data
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"example.com/foo/entity"
+	"example.com/foo/money"
+	"example.com/foo/service"
+)
+
+func TestTypeOverrides(t *testing.T) {
+	ctx := context.Background()
+	db := sqlx.MustOpen("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE foo (id INTEGER PRIMARY KEY, paid INTEGER NOT NULL, extra TEXT NOT NULL, total_amount INTEGER NOT NULL)")
+
+	r := NewFooRepo(db)
+	if err := r.Create(ctx,
+		&entity.Foo{Id: 1, Paid: true, Extra: json.RawMessage(` + "`" + `{"a":1}` + "`" + `), TotalAmount: money.Money{Cents: 100}},
+		&entity.Foo{Id: 2, Extra: json.RawMessage("{}"), TotalAmount: money.Money{Cents: 200}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.GetByID(ctx, 1)
+	if err != nil || !e.Paid || string(e.Extra) != ` + "`" + `{"a":1}` + "`" + ` || e.TotalAmount.Cents != 100 {
+		t.Fatalf("GetByID: %v, %v", e, err)
+	}
+	list, err := r.FindByAmount(ctx, service.FindByAmountWhereParameter{TotalAmountEqual: money.Money{Cents: 100}, PaidEqual: true})
+	if err != nil || len(list) != 1 || list[0].Id != 1 {
+		t.Fatalf("FindByAmount: %v, %v", list, err)
+	}
+	list, err = r.ListByAmounts(ctx, service.ListByAmountsWhereParameter{TotalAmountIn: []money.Money{{Cents: 100}, {Cents: 200}}})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListByAmounts: %v, %v", list, err)
+	}
+}
+`
+
+func TestRunTypeOverrides(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.OverrideContext(t, `CREATE TABLE foo (id bigint NOT NULL primary key, paid tinyint(1) NOT NULL, extra json NOT NULL, total_amount bigint NOT NULL);
+-- fn: FindByAmount
+select * from foo where total_amount = ? and paid = ?;
+-- fn: ListByAmounts
+select * from foo where total_amount in (?);`,
+		&spec.TypeOverride{DBType: "tinyint(1)", GoType: "bool"},
+		&spec.TypeOverride{DBType: "JSON", GoType: "json.RawMessage", Import: "encoding/json"},
+		&spec.TypeOverride{ColumnRegex: regexp.MustCompile(`amount$`), GoType: "money.Money", Import: gentest.Module + "/money", ToDB: "money.ToCents", FromDB: "money.FromCents"},
+	), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "money/money.go", money)
+	gentest.WriteFile(t, dir, "data/type_overrides_test.go", typeOverridesTest)
+	gentest.Test(t, dir, sqliteRequire...)
+}
+
+func TestRunTypeOverridesConversion(t *testing.T) {
+	dxl, err := parser.Parse("CREATE TABLE foo (id bigint NOT NULL primary key, amount bigint DEFAULT NULL);")
+	assert.NoError(t, err)
+	override := &spec.TypeOverride{Column: "foo.*", GoType: "money.Money", ToDB: "money.ToCents", FromDB: "money.FromCents"}
+
+	// the primary key can not be converted
+	assert.Error(t, dxl.DDL[0].Table.SetTypeOverrides([]*spec.TypeOverride{override}))
+
+	// the nullable column can not be converted if its Go type represents NULL
+	override.Column = "foo.amount"
+	dxl.DDL[0].Table.SetNullStrategy(spec.NullPointer)
+	assert.Error(t, dxl.DDL[0].Table.SetTypeOverrides([]*spec.TypeOverride{override}))
+}
+
+// splitLayoutTest 断言仓库接口内嵌了脚手架文件中的扩展接口
+const splitLayoutTest = `package service
+
+var _ FooRepoExtension = FooRepo(nil)
+`
+
+func TestRunSplitLayout(t *testing.T) {
+	dir := t.TempDir()
+	arg := gentest.RunArg(dir)
+	arg.Layout = types.LayoutSplit
+	ctx := gentest.Context(t, "CREATE TABLE foo (id bigint NOT NULL AUTO_INCREMENT, name varchar(64) NOT NULL, PRIMARY KEY (id));")
+	assert.NoError(t, Run(ctx, arg))
+
+	adapter, err := os.ReadFile(filepath.Join(dir, "data", "foo_adapter_gen.go"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(adapter), "// Code generated by codegen. DO NOT EDIT.\n\npackage data\n"))
+	for _, filename := range []string{
+		filepath.Join(dir, "entity", "foo_entity_gen.go"),
+		filepath.Join(dir, "data", "sqlx_tx_gen.go"),
+		filepath.Join(dir, "data", "sqlx_query_gen.go"),
+		filepath.Join(dir, "service", "query_gen.go"),
+		filepath.Join(dir, "service", "foo_repo_gen.go"),
+		filepath.Join(dir, "service", "foo_repo.go"),
+	} {
+		_, err = os.Stat(filename)
+		assert.NoError(t, err)
+	}
+
+	// 脚手架文件只生成一次, 重新生成时保留自定义代码
+	scaffoldFilename := filepath.Join(dir, "data", "foo_adapter.go")
+	custom := "package data\n\nfunc (m *FooAdapter) Custom() {}\n"
+	assert.NoError(t, os.WriteFile(scaffoldFilename, []byte(custom), 0o666))
+	assert.NoError(t, Run(ctx, arg))
+	scaffold, err := os.ReadFile(scaffoldFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, custom, string(scaffold))
+
+	gentest.WriteFile(t, dir, "service/split_test.go", splitLayoutTest)
+	gentest.Vet(t, dir, require...)
+}
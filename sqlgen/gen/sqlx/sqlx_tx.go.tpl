@@ -0,0 +1,49 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+
+    "github.com/jmoiron/sqlx"
+)
+
+type sqlxTxKey struct{}
+
+// NewSqlxTxContext returns a new context carrying the sqlx transaction,
+// the adapters called with the context run in the transaction.
+func NewSqlxTxContext(ctx context.Context, tx *sqlx.Tx) context.Context {
+    return context.WithValue(ctx, sqlxTxKey{}, tx)
+}
+
+// SqlxTxFromContext returns the sqlx transaction in ctx, it returns nil if not exists.
+func SqlxTxFromContext(ctx context.Context) *sqlx.Tx {
+    tx, _ := ctx.Value(sqlxTxKey{}).(*sqlx.Tx)
+    return tx
+}
+
+// SqlxTransaction runs fn in a transaction, it joins the outer transaction if
+// ctx already carries one, otherwise it begins a new transaction and commits
+// it if fn returns nil.
+func SqlxTransaction(ctx context.Context, db *sqlx.DB, fn func(txCtx context.Context) error) (err error) {
+    if SqlxTxFromContext(ctx) != nil {
+        return fn(ctx)
+    }
+
+    tx, err := db.BeginTxx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer func() {
+        if p := recover(); p != nil {
+            _ = tx.Rollback()
+            panic(p)
+        }
+        if err != nil {
+            _ = tx.Rollback()
+        }
+    }()
+
+    if err = fn(NewSqlxTxContext(ctx, tx)); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
@@ -0,0 +1,62 @@
+package gen
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// TemplateFS 是各个后端共用的脚手架模版、审计模版、版本冲突错误和查询条件的模版
+//
+//go:embed scaffold_*.tpl audit.go.tpl version.go.tpl query.go.tpl
+var TemplateFS embed.FS
+
+// loadTemplate 读取名为 name 的模版, 自定义模版目录中存在同名文件时优先使用
+func loadTemplate(dir string, builtin fs.FS, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	data, err := fs.ReadFile(builtin, name)
+	if err != nil {
+		return "", fmt.Errorf("template %q not found: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// ExportTemplates 将内置的模版导出到目录, 作为自定义模版的起点, 已存在的文件不会被覆盖
+func ExportTemplates(dir string, list ...fs.FS) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, builtin := range list {
+		names, err := fs.Glob(builtin, "*.tpl")
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			filename := filepath.Join(dir, name)
+			if _, err := os.Stat(filename); err == nil {
+				fmt.Printf("[ignore] %s already exists\n", filename)
+				continue
+			}
+			data, err := fs.ReadFile(builtin, name)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, data, 0o666); err != nil {
+				return err
+			}
+			fmt.Printf("[export] %s\n", filename)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+CREATE TABLE `users` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `email` varchar(64) NOT NULL,
+  `nick_name` varchar(64) DEFAULT NULL,
+  `age` int DEFAULT NULL,
+  `creator` varchar(64) NOT NULL,
+  `operator` varchar(64) NOT NULL,
+  `created_time` datetime NOT NULL,
+  `updated_time` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_email` (`email`),
+  KEY `idx_nick_name_age` (`nick_name`, `age`)
+);
+
+CREATE TABLE `orders` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `amount` bigint NOT NULL,
+  `version` bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE `order_item` (
+  `tenant_id` bigint NOT NULL,
+  `order_id` varchar(32) NOT NULL,
+  `sku` varchar(32) NOT NULL,
+  PRIMARY KEY (`tenant_id`, `order_id`)
+);
+
+-- fn: FindByIds
+select * from users where id in (?) and age > ?;
+
+-- fn: CountByUser
+select user_id, count(id) AS c from orders where amount > ? group by user_id having c > ? limit ?;
+
+-- fn: RenameUser
+update users set nick_name = ? where id = ?;
+
+-- fn: DeleteOrders
+delete from orders where user_id = ?;
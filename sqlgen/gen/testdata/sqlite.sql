@@ -0,0 +1,6 @@
+-- 表名 order 是保留字
+CREATE TABLE "order" (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL, name TEXT NOT NULL DEFAULT '');
+CREATE UNIQUE INDEX uk_email ON "order" (email);
+
+-- fn: ListByIds
+select * from `order` where id in (?);
@@ -5,8 +5,17 @@ CREATE TABLE `foo`
         UNIQUE KEY `name_index` (`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
 
--- fn: Count
+-- fn: CountAll
 select count(id) AS count from foo;
 
 -- fn: FindOne
-select name, count(id) AS c from foo where id > ? having c > ? limit 1;
\ No newline at end of file
+select name, count(id) AS c from foo where id > ? having c > ? limit 1;
+-- fn: Rename
+begin;
+-- fn: InsertFoo
+insert into foo (name) values (?);
+-- fn: UpdateName
+update foo set name = ? where id = ?;
+-- fn: FindByName
+select * from foo where name = ? limit 1;
+commit;
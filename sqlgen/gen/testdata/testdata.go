@@ -4,3 +4,14 @@ import _ "embed"
 
 //go:embed test.sql
 var TestSql string
+
+// BuildSql 覆盖了唯一键、索引、外键、复合主键、审计列、版本列和可为 NULL 的列,
+// 用于检查生成的代码能否编译
+//
+//go:embed build.sql
+var BuildSql string
+
+// SQLiteSql 是 sqlite 方言的表结构, 表名为保留字, 用于检查生成的 sql 是否按方言为标识符加引号
+//
+//go:embed sqlite.sql
+var SQLiteSql string
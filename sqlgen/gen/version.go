@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"fmt"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// defaultVersionColumns 是未配置时的版本列名
+var defaultVersionColumns = []string{"version", "revision"}
+
+// Version 是表中用于乐观锁的版本列, 不存在时为空.
+// 更新时版本列加一, 条件中匹配实体的版本, 未更新任何行时返回 ErrVersionConflict.
+type Version struct {
+	// Column 版本列的列名
+	Column string
+}
+
+// NewVersion 返回表中的版本列, 版本列需要为 NOT NULL 的整数列.
+// 按表配置的列不存在或类型不符合时报错, 默认的列类型不符合时跳过.
+func NewVersion(table *spec.Table, arg types.RunArg) (Version, error) {
+	config := arg.OptimisticLock
+	name, explicit := config.Tables[table.Name]
+	if explicit && name == "" {
+		return Version{}, nil
+	}
+	names := []string{name}
+	if !explicit {
+		names = config.Columns
+		if len(names) == 0 {
+			names = defaultVersionColumns
+		}
+	}
+
+	for _, name := range names {
+		c, ok := table.GetColumnByName(name)
+		if !ok {
+			if explicit {
+				return Version{}, fmt.Errorf("version column %q is not found in table %q", name, table.Name)
+			}
+			continue
+		}
+		switch c.TP {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+			// 版本列在更新时自增, 需要转换的覆盖类型不支持
+			if c.NotNull && !table.IsPrimary(c.Name) && !c.TypeOverride.Converted() {
+				return Version{Column: c.Name}, nil
+			}
+		}
+		if explicit {
+			return Version{}, fmt.Errorf("version column %q of table %q must be a not null integer without converted type override", name, table.Name)
+		}
+	}
+	return Version{}, nil
+}
+
+// IsValid 返回表中是否存在版本列
+func (v Version) IsValid() bool {
+	return v.Column != ""
+}
+
+// Is 返回 name 是否为版本列
+func (v Version) Is(name string) bool {
+	return v.IsValid() && v.Column == name
+}
+
+// Field 返回版本列在 PO 和实体中的字段名
+func (v Version) Field() string {
+	return strcase.ToCamel(v.Column)
+}
+
+// VersionTempData 是版本冲突错误所在文件的模版数据
+type VersionTempData struct {
+	// RepoPackageName 仓库接口的包名
+	RepoPackageName string
+}
+
+// GenerateVersion 存在版本列时在仓库接口的包中生成 ErrVersionConflict, 每个仓库接口包只生成一次
+func (l Layout) GenerateVersion(arg types.RunArg) error {
+	l.templates = TemplateFS
+	return l.GenerateFile(l.Filename(arg.RepoOutput, "version"), "version.go.tpl", VersionTempData{RepoPackageName: PackageName(arg.RepoOutput)})
+}
@@ -0,0 +1,10 @@
+package {{$.RepoPackageName}}
+
+import (
+    "errors"
+)
+
+// ErrVersionConflict is returned by Update when no row matches the primary key
+// and the version of the entity, the row is usually updated by others since it
+// was read, reload it and retry if necessary.
+var ErrVersionConflict = errors.New("version conflict")
@@ -0,0 +1,37 @@
+package xorm
+
+import (
+	"embed"
+	"text/template"
+
+	"github.com/xyzbit/codegen/sqlgen/gen"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// TemplateFS 是内置的模版, 可以通过 templates_dir 覆盖同名的模版
+//
+//go:embed *.tpl
+var TemplateFS embed.FS
+
+var funcMap = template.FuncMap{
+	"HasIn":      gen.HasIn,
+	"ExpandArgs": expandArgs,
+}
+
+func Run(list []spec.Context, arg types.RunArg) error {
+	return gen.Run(list, arg, gen.Backend{
+		Name:      "xorm",
+		Templates: TemplateFS,
+		FuncMaps:  []template.FuncMap{funcMap},
+		Helpers:   []string{"xorm_tx"},
+		Table: func(td gen.TempData) (gen.Table, error) {
+			return gen.Table{FuncMap: gen.QueryFuncMap(td.Dialect)}, nil
+		},
+	})
+}
+
+// expandArgs 返回传给 XormExpandIn 的参数, in 和 not in 的参数使用 XormIn 包装.
+func expandArgs(dml spec.DML) (string, error) {
+	return gen.WrapInArgs(dml, "po", "XormIn")
+}
@@ -0,0 +1,408 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    {{- if eq $.Dialect "sqlite"}}
+    "strings"
+    {{- end}}
+
+    {{if eq $.Dialect "mysql"}}"github.com/go-sql-driver/mysql"{{end}}
+    "github.com/samber/lo"
+    "xorm.io/xorm"
+    "xorm.io/xorm/schemas"
+    {{- range $.Table.TypeImports}}
+    "{{.}}"
+    {{- end}}
+
+    repo "{{$.RepoPackage}}"
+    entity "{{$.EntityPackage}}"
+)
+
+// {{UpperCamel $.Table.Name}}Adapter represents a {{$.Table.Name}} adapter.
+type {{UpperCamel $.Table.Name}}Adapter struct {
+    engine *xorm.Engine
+}
+
+// New{{UpperCamel $.Table.Name}}Repo returns a new {{$.Table.Name}} adapter implemented {{$.Table.Name}}Repo.
+func New{{UpperCamel $.Table.Name}}Repo (
+    engine *xorm.Engine,
+) repo.{{UpperCamel $.Table.Name}}Repo {
+    return &{{UpperCamel $.Table.Name}}Adapter{engine: engine}
+}
+
+// DB returns the transaction session in ctx if it exists, otherwise returns
+// a new session which is closed automatically after the query.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DB(ctx context.Context) *xorm.Session {
+    if session, ok := XormTxFromContext(ctx); ok {
+        return session
+    }
+    return m.engine.Context(ctx)
+}
+
+// Create creates  {{$.Table.Name}} data.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Create(ctx context.Context, es ...*entity.{{UpperCamel $.Table.Name}}) error {
+    if len(es)==0{
+        return fmt.Errorf("data is empty")
+    }
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.CreateVariables}}
+    {{- end}}
+
+    pos := lo.Map(es, func(v *entity.{{UpperCamel $.Table.Name}}, _ int) *{{UpperCamel $.Table.Name}} {
+        {{- if $.Audit.IsValid}}
+        p := to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{$.Audit.OnCreate "p"}}
+        return p
+        {{- else}}
+        return to{{UpperCamel $.Table.Name}}PO(ctx, v)
+        {{- end}}
+    })
+
+    _, err := m.DB(ctx).Insert(&pos)
+    return err
+}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// GetByKey get {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    has, err := m.DB(ctx).ID(schemas.PK{ {{- range $i, $c := $.Table.PrimaryColumnList}}{{if $i}}, {{end}}key.{{UpperCamel $c.Name}}{{end -}} }).Get(&po)
+    if err != nil {
+        return nil, err
+    }
+    if !has {
+        return nil, sql.ErrNoRows
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- else -}}
+// GetByID get {{$.Table.Name}} by id.
+func (m *{{UpperCamel $.Table.Name}}Adapter) GetByID(ctx context.Context, id {{PrimaryKeyType}}) (*entity.{{UpperCamel $.Table.Name}}, error) {
+    var po {{UpperCamel $.Table.Name}}
+
+    has, err := m.DB(ctx).ID(id).Get(&po)
+    if err != nil {
+        return nil, err
+    }
+    if !has {
+        return nil, sql.ErrNoRows
+    }
+
+    return to{{UpperCamel $.Table.Name}}Entity(ctx, &po), nil
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// UpdateByKey update {{$.Table.Name}} by primary key, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) UpdateByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+    {{- if $.Version.IsValid}}
+
+    affected, err := m.DB(ctx).ID(schemas.PK{ {{- range $i, $c := $.Table.PrimaryColumnList}}{{if $i}}, {{end}}key.{{UpperCamel $c.Name}}{{end -}} }).
+        Incr("{{$.Version.Column}}").
+        Where("{{$.Version.Column}} = ?", p.{{$.Version.Field}}).
+        Update(p)
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}}++
+
+    return nil
+    {{- else}}
+
+    _, err := m.DB(ctx).ID(schemas.PK{ {{- range $i, $c := $.Table.PrimaryColumnList}}{{if $i}}, {{end}}key.{{UpperCamel $c.Name}}{{end -}} }).Update(p)
+    return err
+    {{- end}}
+}
+{{- else -}}
+// Update update {{$.Table.Name}}, the zero value fields are not updated{{template "versionComment" $}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Update(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) error {
+    p := to{{UpperCamel $.Table.Name}}PO(ctx, e)
+    {{- if $.Audit.IsValid}}
+    {{$.Audit.OnUpdate "p"}}
+    {{- end}}
+    {{- if $.Version.IsValid}}
+
+    affected, err := m.DB(ctx).ID(p.{{UpperCamel $.Table.PrimaryColumn.Name}}).
+        Incr("{{$.Version.Column}}").
+        Where("{{$.Version.Column}} = ?", p.{{$.Version.Field}}).
+        Update(p)
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return repo.ErrVersionConflict
+    }
+    e.{{$.Version.Field}}++
+
+    return nil
+    {{- else}}
+
+    _, err := m.DB(ctx).ID(p.{{UpperCamel $.Table.PrimaryColumn.Name}}).Update(p)
+    return err
+    {{- end}}
+}
+{{- end}}
+
+{{if $.Table.HasCompositePrimaryKey -}}
+// DeleteByKey delete {{$.Table.Name}} by primary key.
+func (m *{{UpperCamel $.Table.Name}}Adapter) DeleteByKey(ctx context.Context, key repo.{{$.Table.KeyStructureName}}) error {
+    _, err := m.DB(ctx).ID(schemas.PK{ {{- range $i, $c := $.Table.PrimaryColumnList}}{{if $i}}, {{end}}key.{{UpperCamel $c.Name}}{{end -}} }).Delete(new({{UpperCamel $.Table.Name}}))
+    return err
+}
+{{- else -}}
+// Delete delete {{$.Table.Name}}.
+func (m *{{UpperCamel $.Table.Name}}Adapter) Delete(ctx context.Context, id {{PrimaryKeyType}}) error {
+    _, err := m.DB(ctx).ID(id).Delete(new({{UpperCamel $.Table.Name}}))
+    return err
+}
+{{- end}}
+
+// IsDuplicatedKeyError use to check error is unique key conflict error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsDuplicatedKeyError(err error) bool {
+    {{- if eq $.Dialect "postgres"}}
+    // the errors of github.com/lib/pq and github.com/jackc/pgx both implement SQLState.
+    var pgErr interface{ SQLState() string }
+    return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+    {{- else if eq $.Dialect "sqlite"}}
+    // github.com/mattn/go-sqlite3 and modernc.org/sqlite report the same message.
+    return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+    {{- else}}
+    var mysqlErr *mysql.MySQLError
+    return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+    {{- end}}
+}
+
+// IsNotFoundError use to check error is record not found error.
+func (m *{{UpperCamel $.Table.Name}}Adapter) IsNotFoundError(err error) bool {
+    return errors.Is(err, sql.ErrNoRows)
+}
+
+{{- range $stmt := $.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{- range $tx := $.Transaction}}
+// {{UpperCamel $tx.FuncName}} is generated from sql:
+// {{LineComment $tx.SQL}}
+func (m *{{UpperCamel $.Table.Name}}Adapter) {{UpperCamel $tx.FuncName}}(ctx context.Context{{if $tx.HasParameter}}, arg repo.{{$tx.ParameterStructureName}}{{end}}) ({{if $tx.HasResult}}*repo.{{$tx.ResultStructureName}}, {{end}}error) {
+    {{- if $tx.HasResult}}
+    var result repo.{{$tx.ResultStructureName}}
+    {{- end}}
+    // join the outer transaction if it exists.
+    err := XormTransaction(ctx, m.engine, func(txCtx context.Context) error {
+        var err error
+        {{- range $v := $tx.Statements}}
+        {{- if IsInsert $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data...); err != nil {
+            return err
+        }
+        {{- else if IsSelect $v}}
+        if result.{{UpperCamel $v.FuncName}}, err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Having.IsValid}}, arg.{{UpperCamel $v.FuncName}}Having{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsUpdate $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx, arg.{{UpperCamel $v.FuncName}}Data{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- else if IsDelete $v}}
+        if err = m.{{MethodName $v.FuncName}}(txCtx{{if $v.Where.IsValid}}, arg.{{UpperCamel $v.FuncName}}Where{{end}}{{if $v.Limit.Multiple}}, arg.{{UpperCamel $v.FuncName}}Limit{{end}}); err != nil {
+            return err
+        }
+        {{- end}}
+        {{- end}}
+        return nil
+    })
+    {{- if $tx.HasResult}}
+    if err != nil {
+        return nil, err
+    }
+
+    return &result, nil
+    {{- else}}
+
+    return err
+    {{- end}}
+}
+{{range $stmt := $tx.InsertStmt}}
+{{template "insert" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.SelectStmt}}
+{{template "select" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.UpdateStmt}}
+{{template "update" $stmt}}
+{{end -}}
+{{- range $stmt := $tx.DeleteStmt}}
+{{template "delete" $stmt}}
+{{end -}}
+{{end}}
+
+// {{UpperCamel $.Table.Name}} represents a {{$.Table.Name}} struct data.
+type {{UpperCamel $.Table.Name}} struct { {{range $.Table.Columns}}
+    {{UpperCamel .Name}} {{.POGoType}} `xorm:"'{{.Name}}'{{if IsPrimary .Name}} pk{{end}}{{if .AutoIncrement}} autoincr{{end}}" json:"{{.Name}}"`{{if .HasComment}}// {{TrimNewLine .Comment}}{{end}}{{end}}
+}
+
+// TableName returns the table name of {{UpperCamel $.Table.Name}}.
+func (*{{UpperCamel $.Table.Name}}) TableName() string {
+    return "{{$.Table.Name}}"
+}
+
+func to{{UpperCamel $.Table.Name}}PO(ctx context.Context, e *entity.{{UpperCamel $.Table.Name}}) *{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToPO "e"}},
+        {{- end}}
+    }
+}
+
+func to{{UpperCamel $.Table.Name}}Entity(ctx context.Context, po *{{UpperCamel $.Table.Name}}) *entity.{{UpperCamel $.Table.Name}} {
+    _ = ctx
+    return &entity.{{UpperCamel $.Table.Name}}{
+        {{- range $.Table.Columns}}
+        {{UpperCamel .Name}}: {{.ToEntity "po"}},
+        {{- end}}
+    }
+}
+
+{{define "insert"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data ...*entity.{{UpperCamel $stmt.TableInfo.Name}}) error {
+    if len(data) == 0 {
+        return fmt.Errorf("data is empty")
+    }
+
+    pos := lo.Map(data, func(v *entity.{{UpperCamel $stmt.TableInfo.Name}}, _ int) *{{UpperCamel $stmt.TableInfo.Name}} {
+        return to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, v)
+    })
+
+    _, err := m.DB(ctx).
+        Cols({{- range $i, $c := $stmt.ColumnInfo}}{{if $i}}, {{end}}"{{$c.Name}}"{{end -}}).
+        Insert(&pos)
+    return err
+}
+
+{{end}}
+{{define "select"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Having.IsValid}}, having repo.{{$stmt.Having.ParameterStructureName "Having"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) ({{if $stmt.Limit.One}}*{{else}}[]*{{end}}{{if IsExtraResult $stmt.ReceiverName}}repo.{{$stmt.ReceiverName}}{{else}}entity.{{$stmt.ReceiverName}}{{end}}, error) {
+    {{- template "query" $stmt}}
+    {{- if IsExtraResult $stmt.ReceiverName}}
+    {{- if $stmt.Limit.One}}
+
+    var result repo.{{$stmt.ReceiverName}}
+    has, err := m.DB(ctx).SQL(query, args...).Get(&result)
+    if err != nil {
+        return nil, err
+    }
+    if !has {
+        return nil, sql.ErrNoRows
+    }
+
+    return &result, nil
+    {{- else}}
+
+    var result []*repo.{{$stmt.ReceiverName}}
+    if err := m.DB(ctx).SQL(query, args...).Find(&result); err != nil {
+        return nil, err
+    }
+
+    return result, nil
+    {{- end}}
+    {{- else}}
+    {{- if $stmt.Limit.One}}
+
+    var po {{UpperCamel $stmt.FromInfo.Name}}
+    has, err := m.DB(ctx).SQL(query, args...).Get(&po)
+    if err != nil {
+        return nil, err
+    }
+    if !has {
+        return nil, sql.ErrNoRows
+    }
+
+    return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, &po), nil
+    {{- else}}
+
+    var pos []*{{UpperCamel $stmt.FromInfo.Name}}
+    if err := m.DB(ctx).SQL(query, args...).Find(&pos); err != nil {
+        return nil, err
+    }
+
+    entitys := lo.Map(pos, func(v *{{UpperCamel $stmt.FromInfo.Name}}, _ int) *entity.{{UpperCamel $stmt.FromInfo.Name}} {
+        return to{{UpperCamel $stmt.FromInfo.Name}}Entity(ctx, v)
+    })
+
+    return entitys, nil
+    {{- end}}
+    {{- end}}
+}
+
+{{end}}
+{{define "update"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.TableInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context, data *entity.{{UpperCamel $stmt.TableInfo.Name}}{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    po := to{{UpperCamel $stmt.TableInfo.Name}}PO(ctx, data)
+    {{- template "query" $stmt}}
+
+    if _, err := m.DB(ctx).Exec(append([]interface{}{query}, args...)...); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+{{end}}
+{{define "delete"}}
+{{- $stmt := .}}
+// {{MethodName $stmt.FuncName}} is generated from sql:
+// {{LineComment $stmt.SQL}}
+func (m *{{UpperCamel $stmt.FromInfo.Name}}Adapter) {{MethodName $stmt.FuncName}}(ctx context.Context{{if $stmt.Where.IsValid}}, where repo.{{$stmt.Where.ParameterStructureName "Where"}}{{end}}{{if $stmt.Limit.Multiple}}, limit repo.{{$stmt.Limit.ParameterStructureName}}{{end}}) error {
+    {{- template "query" $stmt}}
+
+    if _, err := m.DB(ctx).Exec(append([]interface{}{query}, args...)...); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+{{end}}
+{{define "query"}}
+{{- $stmt := .}}
+    {{- if HasIn $stmt}}
+    query, args := XormExpandIn({{Query $stmt}}, {{ExpandArgs $stmt}})
+    {{- else}}
+    query := {{Query $stmt}}
+    args := []interface{}{ {{- Args $stmt "po" -}} }
+    {{- end}}
+{{- end}}
+{{define "versionComment"}}
+{{- if $.Version.IsValid}}, {{$.Version.Column}} is increased and repo.ErrVersionConflict is returned if it is out of date{{end}}
+{{- end}}
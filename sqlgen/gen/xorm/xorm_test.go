@@ -0,0 +1,104 @@
+package xorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xyzbit/codegen/sqlgen/gen/gentest"
+	"github.com/xyzbit/codegen/sqlgen/gen/testdata"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parser"
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+	"github.com/xyzbit/codegen/sqlgen/pkg/types"
+)
+
+// require 是生成的代码依赖的模块
+var require = []string{
+	"github.com/go-sql-driver/mysql v1.8.1",
+	"github.com/samber/lo v1.49.1",
+	"xorm.io/xorm v1.3.9",
+}
+
+// findOneTest 使用 sqlite 内存数据库运行带 having 条件的查询, mysql 方言生成的查询 sqlite 同样支持
+const findOneTest = `package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"xorm.io/xorm"
+
+	"example.com/foo/entity"
+	"example.com/foo/service"
+)
+
+func TestFindOne(t *testing.T) {
+	ctx := context.Background()
+	engine, err := xorm.NewEngine("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+	engine.SetMaxOpenConns(1)
+	if _, err := engine.Exec("CREATE TABLE foo (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NULL UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewFooRepo(engine)
+	if err := r.Create(ctx, &entity.Foo{Name: "foo"}, &entity.Foo{Name: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 1, Valid: true}})
+	if err != nil || result.C.Int64 != 2 {
+		t.Fatalf("FindOne: %v, %v", result, err)
+	}
+	if _, err := r.FindOne(ctx, service.FindOneWhereParameter{IdGT: 0}, service.FindOneHavingParameter{CGT: sql.NullInt64{Int64: 2, Valid: true}}); !r.IsNotFoundError(err) {
+		t.Fatalf("FindOne having: %v", err)
+	}
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, testdata.TestSql), gentest.RunArg(dir))
+	assert.NoError(t, err)
+
+	gentest.WriteFile(t, dir, "data/find_one_test.go", findOneTest)
+	gentest.Test(t, dir, append(require, "github.com/mattn/go-sqlite3 v1.14.22")...)
+}
+
+func TestRunBuild(t *testing.T) {
+	for name, layout := range map[string]string{"default": "", "split": types.LayoutSplit} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			arg := gentest.RunArg(dir)
+			arg.Layout = layout
+			arg.AutoAudit = true
+			err := Run(gentest.Context(t, testdata.BuildSql), arg)
+			assert.NoError(t, err)
+			gentest.Vet(t, dir, require...)
+		})
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+	dxl, err := parser.Parse(`CREATE TABLE foo (id bigint NOT NULL primary key, name varchar(255) NOT NULL);
+-- fn: FindByNames
+select * from foo where name not in (?) and id > ?;`)
+	assert.NoError(t, err)
+	ctx, err := spec.From(dxl)
+	assert.NoError(t, err)
+
+	args, err := expandArgs(ctx[0].SelectStmt[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "XormIn(where.NameNotIn), where.IdGT", args)
+}
+
+func TestRunConflictFuncName(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(gentest.Context(t, `CREATE TABLE foo (id bigint NOT NULL primary key);
+-- fn: GetByID
+select * from foo where id = ? limit 1;`), gentest.RunArg(dir))
+	assert.Error(t, err)
+}
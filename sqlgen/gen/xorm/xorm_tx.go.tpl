@@ -0,0 +1,88 @@
+package {{$.AdapterPackageName}}
+
+import (
+    "context"
+    "strings"
+
+    "xorm.io/xorm"
+)
+
+type xormTxKey struct{}
+
+// NewXormTxContext returns a new context carrying the xorm session which has begun
+// a transaction, the adapters called with the context run in the transaction.
+func NewXormTxContext(ctx context.Context, session *xorm.Session) context.Context {
+    return context.WithValue(ctx, xormTxKey{}, session)
+}
+
+// XormTxFromContext returns the transaction session in ctx, ok is false if not exists.
+func XormTxFromContext(ctx context.Context) (session *xorm.Session, ok bool) {
+    session, ok = ctx.Value(xormTxKey{}).(*xorm.Session)
+    return session, ok
+}
+
+// XormTransaction runs fn in a transaction, it joins the outer transaction if
+// ctx already carries one, otherwise it begins a new transaction and commits
+// it if fn returns nil.
+func XormTransaction(ctx context.Context, engine *xorm.Engine, fn func(txCtx context.Context) error) error {
+    if _, ok := XormTxFromContext(ctx); ok {
+        return fn(ctx)
+    }
+
+    session := engine.NewSession().Context(ctx)
+    defer session.Close()
+
+    if err := session.Begin(); err != nil {
+        return err
+    }
+    if err := fn(NewXormTxContext(ctx, session)); err != nil {
+        _ = session.Rollback()
+        return err
+    }
+    return session.Commit()
+}
+
+// xormInArg is the argument of the IN expression.
+type xormInArg []interface{}
+
+// XormIn wraps the argument of the IN expression, it is expanded by XormExpandIn.
+func XormIn[T any](values []T) interface{} {
+    arg := make(xormInArg, 0, len(values))
+    for _, v := range values {
+        arg = append(arg, v)
+    }
+    return arg
+}
+
+// XormExpandIn expands the placeholders of the arguments wrapped by XormIn,
+// xorm passes the arguments of raw sql to the driver as they are.
+func XormExpandIn(query string, args ...interface{}) (string, []interface{}) {
+    var (
+        b       strings.Builder
+        newArgs = make([]interface{}, 0, len(args))
+        i       int
+    )
+    for _, r := range query {
+        if r != '?' || i >= len(args) {
+            b.WriteRune(r)
+            continue
+        }
+
+        in, ok := args[i].(xormInArg)
+        if !ok {
+            b.WriteRune(r)
+            newArgs = append(newArgs, args[i])
+            i++
+            continue
+        }
+        if len(in) == 0 {
+            // an empty IN list matches nothing.
+            b.WriteString("NULL")
+        } else {
+            b.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(in)), ", "))
+        }
+        newArgs = append(newArgs, in...)
+        i++
+    }
+    return b.String(), newArgs
+}
@@ -73,11 +73,21 @@ type (
 		SeqInIndex int    `db:"SEQ_IN_INDEX"`
 	}
 
+	// DbForeignKey defines foreign key of columns in information_schema.KEY_COLUMN_USAGE
+	DbForeignKey struct {
+		ConstraintName       string `db:"CONSTRAINT_NAME"`
+		ColumnName           string `db:"COLUMN_NAME"`
+		OrdinalPosition      int    `db:"ORDINAL_POSITION"`
+		ReferencedTableName  string `db:"REFERENCED_TABLE_NAME"`
+		ReferencedColumnName string `db:"REFERENCED_COLUMN_NAME"`
+	}
+
 	// Table defines table data
 	Table struct {
-		Db      string
-		Table   string
-		Columns []*Column
+		Db          string
+		Table       string
+		Columns     []*Column
+		ForeignKeys []*DbForeignKey
 	}
 )
 
@@ -135,10 +145,16 @@ func (m *InformationSchemaModel) FindColumns(db, table string) (*Table, error) {
 		return list[i].OrdinalPosition < list[j].OrdinalPosition
 	})
 
+	foreignKeys, err := m.FindForeignKeys(db, table)
+	if err != nil {
+		return nil, err
+	}
+
 	var ret Table
 	ret.Db = db
 	ret.Table = table
 	ret.Columns = list
+	ret.ForeignKeys = foreignKeys
 	return &ret, nil
 }
 
@@ -153,3 +169,16 @@ func (m *InformationSchemaModel) FindIndex(db, table, column string) ([]*DbIndex
 
 	return reply, nil
 }
+
+// FindForeignKeys finds the foreign keys which reference the tables in the same database,
+// the columns of a foreign key are in order.
+func (m *InformationSchemaModel) FindForeignKeys(db, table string) ([]*DbForeignKey, error) {
+	querySql := `SELECT k.CONSTRAINT_NAME,k.COLUMN_NAME,k.ORDINAL_POSITION,k.REFERENCED_TABLE_NAME,k.REFERENCED_COLUMN_NAME from KEY_COLUMN_USAGE k WHERE k.TABLE_SCHEMA = ? and k.TABLE_NAME = ? and k.REFERENCED_TABLE_SCHEMA = ? ORDER BY k.CONSTRAINT_NAME,k.ORDINAL_POSITION`
+	var reply []*DbForeignKey
+	err := m.conn.QueryRowsPartial(&reply, querySql, db, table, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
@@ -38,6 +38,8 @@ func TestInformationSchemaModel_GetAllTables(t *testing.T) {
 	assert.ErrorIs(t, err, dummyError)
 }
 
+var foreignKeyQuery = `SELECT k.CONSTRAINT_NAME,k.COLUMN_NAME,k.ORDINAL_POSITION,k.REFERENCED_TABLE_NAME,k.REFERENCED_COLUMN_NAME from KEY_COLUMN_USAGE k WHERE k.TABLE_SCHEMA = ? and k.TABLE_NAME = ? and k.REFERENCED_TABLE_SCHEMA = ? ORDER BY k.CONSTRAINT_NAME,k.ORDINAL_POSITION`
+
 func TestInformationSchemaModel_FindColumns(t *testing.T) {
 	logx.Disable()
 	var indexQuery = `SELECT s.INDEX_NAME,s.NON_UNIQUE,s.SEQ_IN_INDEX from  STATISTICS s  WHERE  s.TABLE_SCHEMA = ? and s.TABLE_NAME = ? and s.COLUMN_NAME = ?`
@@ -49,13 +51,18 @@ func TestInformationSchemaModel_FindColumns(t *testing.T) {
 
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	assert.NoError(t, err)
-	mock.ExpectQuery(query).WithArgs(database, table).WillReturnRows(sqlmock.NewRows(mockTableNames))
+	mock.ExpectQuery(query).WithArgs(database, table).WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow(column))
 	mock.ExpectQuery(indexQuery).WithArgs(database, table, column).WillReturnRows(sqlmock.NewRows(mockTableNames))
+	mock.ExpectQuery(foreignKeyQuery).WithArgs(database, table, database).WillReturnRows(
+		sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"}).
+			AddRow("fk_bar_baz", column, 1, "qux", "id"))
 
 	conn := sqlx.NewSqlConnFromDB(db)
 	model := NewInformationSchemaModel(conn)
-	_, err = model.FindColumns(database, table)
+	ret, err := model.FindColumns(database, table)
 	assert.NoError(t, err)
+	assert.Equal(t, []*DbForeignKey{{ConstraintName: "fk_bar_baz", ColumnName: column, OrdinalPosition: 1, ReferencedTableName: "qux", ReferencedColumnName: "id"}}, ret.ForeignKeys)
+	assert.NoError(t, mock.ExpectationsWereMet())
 
 	db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	assert.NoError(t, err)
@@ -87,3 +94,29 @@ func TestInformationSchemaModel_FindIndex(t *testing.T) {
 	_, err = model.FindIndex(database, table, column)
 	assert.ErrorIs(t, err, dummyError)
 }
+
+func TestInformationSchemaModel_FindForeignKeys(t *testing.T) {
+	logx.Disable()
+	var database = "foo"
+	var table = "bar"
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery(foreignKeyQuery).WithArgs(database, table, database).WillReturnRows(
+		sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"}).
+			AddRow("fk_bar", "tenant_id", 1, "baz", "tenant_id").
+			AddRow("fk_bar", "baz_id", 2, "baz", "id"))
+
+	conn := sqlx.NewSqlConnFromDB(db)
+	model := &InformationSchemaModel{conn: conn}
+	foreignKeys, err := model.FindForeignKeys(database, table)
+	assert.NoError(t, err)
+	assert.Equal(t, []*DbForeignKey{
+		{ConstraintName: "fk_bar", ColumnName: "tenant_id", OrdinalPosition: 1, ReferencedTableName: "baz", ReferencedColumnName: "tenant_id"},
+		{ConstraintName: "fk_bar", ColumnName: "baz_id", OrdinalPosition: 2, ReferencedTableName: "baz", ReferencedColumnName: "id"},
+	}, foreignKeys)
+
+	mock.ExpectQuery(foreignKeyQuery).WithArgs(database, table, database).WillReturnError(dummyError)
+	_, err = model.FindForeignKeys(database, table)
+	assert.ErrorIs(t, err, dummyError)
+}
@@ -0,0 +1,93 @@
+package infoschema
+
+import (
+	"sort"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ IInformationSchema = (*PostgresSchemaModel)(nil)
+
+// PostgresSchemaModel reads the tables of a postgres schema from pg_catalog and
+// information_schema, the result is in the same form as InformationSchemaModel:
+//   - the database is the schema name, e.g. public
+//   - DATA_TYPE is the type returned by format_type, e.g. character varying(255), text[]
+//   - EXTRA is auto_increment for the serial and identity columns
+//   - the index name of the primary key is PRIMARY
+type PostgresSchemaModel struct {
+	conn sqlx.SqlConn
+}
+
+// NewPostgresSchemaModel creates an instance for PostgresSchemaModel
+func NewPostgresSchemaModel(conn sqlx.SqlConn) IInformationSchema {
+	return &PostgresSchemaModel{conn: conn}
+}
+
+// GetAllTables selects all tables from the schema
+func (m *PostgresSchemaModel) GetAllTables(schema string) ([]string, error) {
+	query := `select table_name from information_schema.tables where table_schema = $1 and table_type = 'BASE TABLE'`
+	var tables []string
+	err := m.conn.QueryRows(&tables, query, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// FindColumns return columns in specified schema and table
+func (m *PostgresSchemaModel) FindColumns(schema, table string) (*Table, error) {
+	querySql := `SELECT a.attname AS "COLUMN_NAME", format_type(a.atttypid, a.atttypmod) AS "DATA_TYPE", format_type(a.atttypid, a.atttypmod) AS "COLUMN_TYPE", CASE WHEN a.attidentity <> '' OR pg_get_expr(d.adbin, d.adrelid) LIKE 'nextval(%' THEN 'auto_increment' ELSE '' END AS "EXTRA", COALESCE(col_description(a.attrelid, a.attnum), '') AS "COLUMN_COMMENT", pg_get_expr(d.adbin, d.adrelid) AS "COLUMN_DEFAULT", CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END AS "IS_NULLABLE", a.attnum AS "ORDINAL_POSITION" FROM pg_catalog.pg_attribute a JOIN pg_catalog.pg_class c ON c.oid = a.attrelid JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped`
+	var reply []*DbColumn
+	err := m.conn.QueryRowsPartial(&reply, querySql, schema, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Column
+	for _, item := range reply {
+		index, err := m.FindIndex(schema, table, item.Name)
+		if err != nil {
+			if err != sqlx.ErrNotFound {
+				return nil, err
+			}
+			continue
+		}
+
+		if len(index) > 0 {
+			for _, i := range index {
+				list = append(list, &Column{
+					DbColumn: item,
+					Index:    i,
+				})
+			}
+		} else {
+			list = append(list, &Column{
+				DbColumn: item,
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].OrdinalPosition < list[j].OrdinalPosition
+	})
+
+	var ret Table
+	ret.Db = schema
+	ret.Table = table
+	ret.Columns = list
+	return &ret, nil
+}
+
+// FindIndex finds index with given schema, table and column, the expressions
+// and the included columns of the index are ignored.
+func (m *PostgresSchemaModel) FindIndex(schema, table, column string) ([]*DbIndex, error) {
+	querySql := `SELECT CASE WHEN x.indisprimary THEN 'PRIMARY' ELSE i.relname END AS "INDEX_NAME", CASE WHEN x.indisunique THEN 0 ELSE 1 END AS "NON_UNIQUE", array_position(x.indkey::int2[], a.attnum) AS "SEQ_IN_INDEX" FROM pg_catalog.pg_index x JOIN pg_catalog.pg_class i ON i.oid = x.indexrelid JOIN pg_catalog.pg_class c ON c.oid = x.indrelid JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(x.indkey) WHERE n.nspname = $1 AND c.relname = $2 AND a.attname = $3 AND array_position(x.indkey::int2[], a.attnum) <= x.indnkeyatts`
+	var reply []*DbIndex
+	err := m.conn.QueryRowsPartial(&reply, querySql, schema, table, column)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
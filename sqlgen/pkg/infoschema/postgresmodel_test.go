@@ -0,0 +1,82 @@
+package infoschema
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestPostgresSchemaModel_GetAllTables(t *testing.T) {
+	logx.Disable()
+	var query = `select table_name from information_schema.tables where table_schema = $1 and table_type = 'BASE TABLE'`
+	var schema = "public"
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery(query).WithArgs(schema).WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("foo").AddRow("bar"))
+
+	conn := sqlx.NewSqlConnFromDB(db)
+	model := NewPostgresSchemaModel(conn)
+	tables, err := model.GetAllTables(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, tables)
+
+	mock.ExpectQuery(query).WithArgs(schema).WillReturnError(dummyError)
+	_, err = model.GetAllTables(schema)
+	assert.ErrorIs(t, err, dummyError)
+}
+
+func TestPostgresSchemaModel_FindColumns(t *testing.T) {
+	logx.Disable()
+	var schema = "public"
+	var table = "foo"
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectQuery(`FROM pg_catalog.pg_attribute`).WithArgs(schema, table).WillReturnRows(
+		sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE", "COLUMN_TYPE", "EXTRA", "COLUMN_COMMENT", "COLUMN_DEFAULT", "IS_NULLABLE", "ORDINAL_POSITION"}).
+			AddRow("name", "text", "text", "", "", nil, "YES", 2).
+			AddRow("id", "bigint", "bigint", "auto_increment", "the id", "nextval('foo_id_seq'::regclass)", "NO", 1))
+	mock.ExpectQuery(`FROM pg_catalog.pg_index`).WithArgs(schema, table, "name").WillReturnRows(
+		sqlmock.NewRows([]string{"INDEX_NAME", "NON_UNIQUE", "SEQ_IN_INDEX"}))
+	mock.ExpectQuery(`FROM pg_catalog.pg_index`).WithArgs(schema, table, "id").WillReturnRows(
+		sqlmock.NewRows([]string{"INDEX_NAME", "NON_UNIQUE", "SEQ_IN_INDEX"}).AddRow("PRIMARY", 0, 1))
+
+	conn := sqlx.NewSqlConnFromDB(db)
+	model := NewPostgresSchemaModel(conn)
+	ret, err := model.FindColumns(schema, table)
+	assert.NoError(t, err)
+	assert.Len(t, ret.Columns, 2)
+	assert.Equal(t, "id", ret.Columns[0].Name)
+	assert.Equal(t, "PRIMARY", ret.Columns[0].Index.IndexName)
+	assert.Nil(t, ret.Columns[1].Index)
+
+	mock.ExpectQuery(`FROM pg_catalog.pg_attribute`).WithArgs(schema, table).WillReturnError(dummyError)
+	_, err = model.FindColumns(schema, table)
+	assert.ErrorIs(t, err, dummyError)
+}
+
+func TestPostgresSchemaModel_FindIndex(t *testing.T) {
+	logx.Disable()
+	var schema = "public"
+	var table = "foo"
+	var column = "bar"
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectQuery(`FROM pg_catalog.pg_index`).WithArgs(schema, table, column).WillReturnRows(
+		sqlmock.NewRows([]string{"INDEX_NAME", "NON_UNIQUE", "SEQ_IN_INDEX"}).AddRow("foo_bar_key", 0, 1))
+
+	conn := sqlx.NewSqlConnFromDB(db)
+	model := NewPostgresSchemaModel(conn)
+	index, err := model.FindIndex(schema, table, column)
+	assert.NoError(t, err)
+	assert.Equal(t, []*DbIndex{{IndexName: "foo_bar_key", NonUnique: 0, SeqInIndex: 1}}, index)
+
+	mock.ExpectQuery(`FROM pg_catalog.pg_index`).WithArgs(schema, table, column).WillReturnError(dummyError)
+	_, err = model.FindIndex(schema, table, column)
+	assert.ErrorIs(t, err, dummyError)
+}
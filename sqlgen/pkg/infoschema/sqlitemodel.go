@@ -0,0 +1,98 @@
+package infoschema
+
+import (
+	"sort"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ IInformationSchema = (*SQLiteSchemaModel)(nil)
+
+// SQLiteSchemaModel reads the tables of a sqlite database from sqlite_master
+// and the pragma functions, the result is in the same form as InformationSchemaModel:
+//   - the database is the schema name, it is always main since the tables
+//     are read from sqlite_master
+//   - the parameters are numbered as $1, $2 by their first appearance, so that
+//     they are bound correctly by the driver and formatted correctly by sqlx
+//   - DATA_TYPE is the declared type of the column, e.g. VARCHAR(255), it may be empty
+//   - EXTRA is auto_increment for the alias of the rowid, e.g. id INTEGER PRIMARY KEY
+//   - the index name of the primary key is PRIMARY
+type SQLiteSchemaModel struct {
+	conn sqlx.SqlConn
+}
+
+// NewSQLiteSchemaModel creates an instance for SQLiteSchemaModel
+func NewSQLiteSchemaModel(conn sqlx.SqlConn) IInformationSchema {
+	return &SQLiteSchemaModel{conn: conn}
+}
+
+// GetAllTables selects all tables from sqlite_master, the internal tables such
+// as sqlite_sequence are ignored.
+func (m *SQLiteSchemaModel) GetAllTables(schema string) ([]string, error) {
+	query := `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`
+	var tables []string
+	err := m.conn.QueryRows(&tables, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// FindColumns return columns in specified schema and table
+func (m *SQLiteSchemaModel) FindColumns(schema, table string) (*Table, error) {
+	querySql := `SELECT c.name AS COLUMN_NAME, c.type AS DATA_TYPE, c.type AS COLUMN_TYPE, CASE WHEN c.pk = 1 AND upper(c.type) = 'INTEGER' AND (SELECT count(*) FROM pragma_table_info($1, $2) WHERE pk > 0) = 1 AND (SELECT sql FROM sqlite_master WHERE type = 'table' AND name = $1) NOT LIKE '%WITHOUT ROWID%' THEN 'auto_increment' ELSE '' END AS EXTRA, '' AS COLUMN_COMMENT, c.dflt_value AS COLUMN_DEFAULT, CASE WHEN c."notnull" = 1 OR c.pk > 0 THEN 'NO' ELSE 'YES' END AS IS_NULLABLE, c.cid + 1 AS ORDINAL_POSITION FROM pragma_table_info($1, $2) c`
+	var reply []*DbColumn
+	err := m.conn.QueryRowsPartial(&reply, querySql, table, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Column
+	for _, item := range reply {
+		index, err := m.FindIndex(schema, table, item.Name)
+		if err != nil {
+			if err != sqlx.ErrNotFound {
+				return nil, err
+			}
+			continue
+		}
+
+		if len(index) > 0 {
+			for _, i := range index {
+				list = append(list, &Column{
+					DbColumn: item,
+					Index:    i,
+				})
+			}
+		} else {
+			list = append(list, &Column{
+				DbColumn: item,
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].OrdinalPosition < list[j].OrdinalPosition
+	})
+
+	var ret Table
+	ret.Db = schema
+	ret.Table = table
+	ret.Columns = list
+	return &ret, nil
+}
+
+// FindIndex finds index with given schema, table and column, the primary key is
+// read from table_info since the alias of the rowid has no index, and the
+// expressions of the index are ignored.
+func (m *SQLiteSchemaModel) FindIndex(schema, table, column string) ([]*DbIndex, error) {
+	querySql := `SELECT 'PRIMARY' AS INDEX_NAME, 0 AS NON_UNIQUE, c.pk AS SEQ_IN_INDEX FROM pragma_table_info($1, $2) c WHERE c.name = $3 AND c.pk > 0 UNION ALL SELECT l.name AS INDEX_NAME, CASE WHEN l."unique" = 1 THEN 0 ELSE 1 END AS NON_UNIQUE, i.seqno + 1 AS SEQ_IN_INDEX FROM pragma_index_list($1, $2) l JOIN pragma_index_info(l.name, $2) i WHERE i.name = $3 AND l.origin <> 'pk'`
+	var reply []*DbIndex
+	err := m.conn.QueryRowsPartial(&reply, querySql, table, schema, column)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,93 @@
+package infoschema
+
+import (
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func newSQLiteConn(t *testing.T) sqlx.SqlConn {
+	logx.Disable()
+	conn := sqlx.NewSqlConn("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	for _, stmt := range []string{
+		`CREATE TABLE foo (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL DEFAULT '', email TEXT UNIQUE, age INT)`,
+		`CREATE INDEX foo_name_age ON foo (name, age)`,
+		`CREATE TABLE bar (k TEXT, v BLOB, PRIMARY KEY (k, v)) WITHOUT ROWID`,
+		`CREATE TABLE baz (id INTEGER PRIMARY KEY, n INTEGER) WITHOUT ROWID`,
+	} {
+		_, err := conn.Exec(stmt)
+		assert.NoError(t, err)
+	}
+	return conn
+}
+
+func TestSQLiteSchemaModel_GetAllTables(t *testing.T) {
+	model := NewSQLiteSchemaModel(newSQLiteConn(t))
+	tables, err := model.GetAllTables("main")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"foo", "bar", "baz"}, tables)
+}
+
+func TestSQLiteSchemaModel_FindColumns(t *testing.T) {
+	model := NewSQLiteSchemaModel(newSQLiteConn(t))
+
+	t.Run("rowid", func(t *testing.T) {
+		table, err := model.FindColumns("main", "foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "main", table.Db)
+		assert.Equal(t, "foo", table.Table)
+
+		var (
+			columns []DbColumn
+			indexes = map[string][]DbIndex{}
+		)
+		for _, c := range table.Columns {
+			if len(columns) == 0 || columns[len(columns)-1].Name != c.Name {
+				columns = append(columns, *c.DbColumn)
+			}
+			if c.Index != nil {
+				indexes[c.Name] = append(indexes[c.Name], *c.Index)
+			}
+		}
+		assert.Equal(t, []DbColumn{
+			{Name: "id", DataType: "INTEGER", ColumnType: "INTEGER", Extra: "auto_increment", IsNullAble: "NO", OrdinalPosition: 1},
+			{Name: "name", DataType: "VARCHAR(255)", ColumnType: "VARCHAR(255)", ColumnDefault: "''", IsNullAble: "NO", OrdinalPosition: 2},
+			{Name: "email", DataType: "TEXT", ColumnType: "TEXT", IsNullAble: "YES", OrdinalPosition: 3},
+			{Name: "age", DataType: "INT", ColumnType: "INT", IsNullAble: "YES", OrdinalPosition: 4},
+		}, columns)
+		assert.Equal(t, map[string][]DbIndex{
+			"id":    {{IndexName: "PRIMARY", NonUnique: 0, SeqInIndex: 1}},
+			"name":  {{IndexName: "foo_name_age", NonUnique: 1, SeqInIndex: 1}},
+			"email": {{IndexName: "sqlite_autoindex_foo_1", NonUnique: 0, SeqInIndex: 1}},
+			"age":   {{IndexName: "foo_name_age", NonUnique: 1, SeqInIndex: 2}},
+		}, indexes)
+	})
+
+	t.Run("withoutRowID", func(t *testing.T) {
+		for _, name := range []string{"bar", "baz"} {
+			table, err := model.FindColumns("main", name)
+			assert.NoError(t, err)
+			for _, c := range table.Columns {
+				assert.Empty(t, c.Extra, c.Name)
+				if c.Index != nil {
+					assert.Equal(t, "PRIMARY", c.Index.IndexName)
+				}
+			}
+		}
+	})
+}
+
+func TestSQLiteSchemaModel_FindIndex(t *testing.T) {
+	model := NewSQLiteSchemaModel(newSQLiteConn(t))
+	index, err := model.FindIndex("main", "bar", "v")
+	assert.NoError(t, err)
+	assert.Equal(t, []*DbIndex{{IndexName: "PRIMARY", NonUnique: 0, SeqInIndex: 2}}, index)
+
+	index, err = model.FindIndex("main", "foo", "missing")
+	assert.NoError(t, err)
+	assert.Empty(t, index)
+}
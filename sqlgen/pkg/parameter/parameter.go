@@ -1,6 +1,8 @@
 package parameter
 
 import (
+	"fmt"
+
 	"github.com/xyzbit/codegen/pkg/set"
 	"github.com/xyzbit/codegen/pkg/stringx"
 )
@@ -17,6 +19,9 @@ type Parameter struct {
 	Type string
 	// ThirdPkg represents a go type which is a third package or go built-in package.
 	ThirdPkg string
+	// Converter is the format which converts the parameter to the query argument,
+	// e.g. money.ToCents(%s), it is empty if the parameter is passed as is.
+	Converter string
 }
 
 // Parameters returns the parameters.
@@ -25,6 +30,15 @@ type Parameters []Parameter
 // Empty is a placeholder of Parameters.
 var Empty = Parameters{}
 
+// Arg returns the query argument of the parameter which is a field of the variable pkg.
+func (v Parameter) Arg(pkg string) string {
+	arg := fmt.Sprintf("%s.%s", pkg, v.Column)
+	if v.Converter == "" {
+		return arg
+	}
+	return fmt.Sprintf(v.Converter, arg)
+}
+
 func New() *p {
 	return &p{s: set.From()}
 }
@@ -0,0 +1,10 @@
+{{range .unique_indexes}}
+-- fn: GetBy{{.UniqueNameJoin}}
+select {{.SelectColumns}} from `{{.Table}}` where {{.WhereClause}} limit 1;
+{{end}}
+{{range .general_indexes}}
+{{range .Items}}
+-- fn: ListBy{{.IndexNameJoin}}
+select {{.SelectColumns}} from `{{.Table}}` where {{.WhereClause}};
+{{end}}
+{{end}}
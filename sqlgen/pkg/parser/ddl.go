@@ -0,0 +1,709 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+var (
+	errorUnterminatedString  = errors.New("unterminated quoted string")
+	errorUnterminatedComment = errors.New("unterminated comment")
+	errorUnexpectedEnd       = errors.New("unexpected end of statement")
+)
+
+// ddlSchema is the tables defined by the DDL statements.
+type ddlSchema struct {
+	tables []*spec.Table
+	// skipped are the tables which are not supported, e.g. the partitions,
+	// the statements which refer to them are ignored.
+	skipped map[string]struct{}
+}
+
+func (s *ddlSchema) ddl() []*spec.DDL {
+	var list []*spec.DDL
+	for _, t := range s.tables {
+		s.referPrimaryKey(t)
+		// the columns of the primary key are not null implicitly.
+		for _, columns := range t.Constraint.PrimaryKey {
+			for _, c := range columns {
+				if column := findColumn(t, c); column != nil {
+					column.NotNull = true
+				}
+			}
+		}
+		list = append(list, &spec.DDL{Table: t})
+	}
+	return list
+}
+
+// referPrimaryKey sets the referenced columns of the foreign keys which omit
+// them to the primary key of the referenced table.
+func (s *ddlSchema) referPrimaryKey(t *spec.Table) {
+	for key, foreignKey := range t.Constraint.ForeignKey {
+		if len(foreignKey.ReferColumns) > 0 {
+			continue
+		}
+		refer, _ := s.table(foreignKey.ReferTable)
+		if refer == nil {
+			continue
+		}
+		for _, columns := range refer.Constraint.PrimaryKey {
+			foreignKey.ReferColumns = columns
+		}
+		t.Constraint.ForeignKey[key] = foreignKey
+	}
+}
+
+// table returns the table by name, it returns nil without error if the table is skipped.
+func (s *ddlSchema) table(name string) (*spec.Table, error) {
+	for _, t := range s.tables {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	if _, ok := s.skipped[name]; ok {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("table %q is not defined", name)
+}
+
+func (s *ddlSchema) skip(name string) {
+	if s.skipped == nil {
+		s.skipped = map[string]struct{}{}
+	}
+	s.skipped[name] = struct{}{}
+}
+
+// createIndex parses CREATE INDEX, the syntax of postgres and sqlite are
+// compatible, the expressions and the partial conditions are ignored.
+func (s *ddlSchema) createIndex(p *ddlParser, unique bool) error {
+	p.acceptKeyword("index")
+	p.acceptKeyword("concurrently")
+	p.acceptKeyword("if", "not", "exists")
+	var name string
+	if !p.isKeyword("on") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+	if !p.acceptKeyword("on") {
+		return errorUnsupportedStmt
+	}
+	p.acceptKeyword("only")
+	tableName, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if p.acceptKeyword("using") {
+		p.next()
+	}
+	columns, err := p.columns()
+	if err != nil {
+		return err
+	}
+	table, err := s.table(tableName)
+	if err != nil || table == nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	if unique {
+		appendUniqueKey(table, name, columns...)
+		return nil
+	}
+	if len(name) == 0 {
+		name = keyName(table.Name, columns, "idx")
+	}
+	table.Constraint.AppendIndex(name, columns...)
+	return nil
+}
+
+// dxl parses the queries and returns them with the tables. The queries of all
+// dialects are parsed by the MySQL parser, so the postgres syntax such as the
+// $1 placeholders, the ::type casts and ILIKE are not supported, the queries
+// use ? placeholders which are rebound by the generated code.
+func (s *ddlSchema) dxl(queries []string) (*spec.DXL, error) {
+	ret := &spec.DXL{}
+	if len(queries) > 0 {
+		dxl, err := parse(strings.Join(queries, ";") + ";")
+		if err != nil {
+			return nil, err
+		}
+		ret = dxl
+	}
+	ret.DDL = append(s.ddl(), ret.DDL...)
+
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// references parses the REFERENCES clause of the foreign key on the columns,
+// the referenced columns may be omitted, the MATCH, ON DELETE, ON UPDATE and
+// DEFERRABLE options are skipped.
+func references(table *spec.Table, p *ddlParser, key string, columns ...string) error {
+	referTable, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	var referColumns []string
+	if p.isSymbol("(") {
+		if referColumns, err = p.columns(); err != nil {
+			return err
+		}
+	}
+	for {
+		switch {
+		case p.acceptKeyword("match"), p.acceptKeyword("initially"):
+			p.next()
+		case p.acceptKeyword("on", "delete"), p.acceptKeyword("on", "update"):
+			switch {
+			case p.acceptKeyword("set", "null"), p.acceptKeyword("set", "default"):
+				if p.isSymbol("(") {
+					p.skipGroup()
+				}
+			case p.acceptKeyword("no", "action"):
+			default:
+				// CASCADE or RESTRICT
+				p.next()
+			}
+		case p.acceptKeyword("not", "deferrable"), p.acceptKeyword("deferrable"):
+		default:
+			if len(columns) == 0 {
+				return nil
+			}
+			if len(key) == 0 {
+				key = keyName(table.Name, columns, "fkey")
+			}
+			table.Constraint.AppendForeignKey(key, spec.ForeignKey{
+				Columns:      columns,
+				ReferTable:   referTable,
+				ReferColumns: referColumns,
+			})
+			return nil
+		}
+	}
+}
+
+func findColumn(t *spec.Table, name string) *spec.Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+func appendPrimaryKey(table *spec.Table, key string, columns ...string) {
+	if len(columns) == 0 {
+		return
+	}
+	if len(key) == 0 {
+		key = table.Name + "_pkey"
+	}
+	table.Constraint.AppendPrimaryKey(key, columns...)
+}
+
+func appendUniqueKey(table *spec.Table, key string, columns ...string) {
+	if len(columns) == 0 {
+		return
+	}
+	if len(key) == 0 {
+		key = keyName(table.Name, columns, "key")
+	}
+	table.Constraint.AppendUniqueKey(key, columns...)
+}
+
+// keyName returns the name which postgres chooses for the unnamed constraints and
+// indexes, it is also used for the unnamed indexes of other dialects.
+func keyName(table string, columns []string, suffix string) string {
+	return strings.Join(append(append([]string{table}, columns...), suffix), "_")
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+// token is a token of the DDL statement, the value of the unquoted identifier
+// is lowercase, the value of the quoted identifier and string is unquoted.
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// ddlParser is a simple parser of the DDL statements, it only reads the parts
+// which are used to build spec.Table and skips the others.
+type ddlParser struct {
+	tokens []token
+	pos    int
+	// options are the keywords which start a column constraint, they end
+	// the type and the expressions of the column definition.
+	options map[string]struct{}
+}
+
+// sub returns a parser of the tokens with the same options.
+func (p *ddlParser) sub(tokens []token) *ddlParser {
+	return &ddlParser{tokens: tokens, options: p.options}
+}
+
+func (p *ddlParser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) peek(i int) token {
+	if p.pos+i >= len(p.tokens) {
+		return token{}
+	}
+	return p.tokens[p.pos+i]
+}
+
+func (p *ddlParser) next() token {
+	t := p.peek(0)
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword returns true if the following tokens are the keywords.
+func (p *ddlParser) isKeyword(words ...string) bool {
+	for i, w := range words {
+		t := p.peek(i)
+		if t.kind != tokenIdent || t.value != w {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *ddlParser) acceptKeyword(words ...string) bool {
+	if !p.isKeyword(words...) {
+		return false
+	}
+	p.pos += len(words)
+	return true
+}
+
+// hasKeyword returns true if the keyword appears outside the parentheses.
+func (p *ddlParser) hasKeyword(word string) bool {
+	depth := 0
+	for _, t := range p.tokens[p.pos:] {
+		switch {
+		case t.kind == tokenSymbol && t.value == "(":
+			depth++
+		case t.kind == tokenSymbol && t.value == ")":
+			depth--
+		case depth == 0 && t.kind == tokenIdent && t.value == word:
+			return true
+		}
+	}
+	return false
+}
+
+// isQuery returns true if the statement is a query which is parsed by Parse, the
+// select statements without from clause are not queries of tables, e.g.
+// SELECT pg_catalog.set_config(...) in the dump of postgres.
+func (p *ddlParser) isQuery() bool {
+	switch {
+	case p.isKeyword("select"):
+		return p.hasKeyword("from")
+	case p.isKeyword("insert"), p.isKeyword("update"), p.isKeyword("delete"),
+		p.isKeyword("begin"), p.isKeyword("start"), p.isKeyword("commit"):
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ddlParser) isSymbol(s string) bool {
+	t := p.peek(0)
+	return t.kind == tokenSymbol && t.value == s
+}
+
+func (p *ddlParser) isColumnOption() bool {
+	t := p.peek(0)
+	if t.kind != tokenIdent {
+		return false
+	}
+	_, ok := p.options[t.value]
+	return ok
+}
+
+func (p *ddlParser) name() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenIdent, tokenQuotedIdent:
+		return t.value, nil
+	case tokenEOF:
+		return "", errorUnexpectedEnd
+	default:
+		return "", fmt.Errorf("unexpected %q, expected a name", t.value)
+	}
+}
+
+// qualifiedNames returns the parts of the dotted name, e.g. schema.table.column.
+func (p *ddlParser) qualifiedNames() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.isSymbol(".") {
+			return names, nil
+		}
+		p.next()
+	}
+}
+
+// qualifiedName returns the last part of the dotted name, the schema is ignored.
+func (p *ddlParser) qualifiedName() (string, error) {
+	names, err := p.qualifiedNames()
+	if err != nil {
+		return "", err
+	}
+	return names[len(names)-1], nil
+}
+
+// group returns the comma separated elements in the parentheses.
+func (p *ddlParser) group() ([][]token, error) {
+	if !p.isSymbol("(") {
+		t := p.peek(0)
+		if t.kind == tokenEOF {
+			return nil, errorUnexpectedEnd
+		}
+		return nil, fmt.Errorf("unexpected %q, expected '('", t.value)
+	}
+
+	start, depth := p.pos+1, 0
+	for !p.eof() {
+		t := p.next()
+		if t.kind != tokenSymbol {
+			continue
+		}
+		switch t.value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		}
+		if depth == 0 {
+			return p.sub(p.tokens[start : p.pos-1]).elements(), nil
+		}
+	}
+	return nil, errorUnexpectedEnd
+}
+
+// elements splits the rest tokens by the commas outside the parentheses.
+func (p *ddlParser) elements() [][]token {
+	var (
+		list  [][]token
+		start = p.pos
+		depth int
+	)
+	for ; p.pos < len(p.tokens); p.pos++ {
+		t := p.tokens[p.pos]
+		if t.kind != tokenSymbol {
+			continue
+		}
+		switch t.value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case ",":
+			if depth == 0 {
+				list = append(list, p.tokens[start:p.pos])
+				start = p.pos + 1
+			}
+		}
+	}
+	if start < len(p.tokens) {
+		list = append(list, p.tokens[start:])
+	}
+	return list
+}
+
+// columns returns the column names in the parentheses, the expressions are ignored.
+func (p *ddlParser) columns() ([]string, error) {
+	elements, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+	for _, element := range elements {
+		if len(element) == 0 {
+			continue
+		}
+		first := element[0]
+		if first.kind != tokenIdent && first.kind != tokenQuotedIdent {
+			continue
+		}
+		if len(element) > 1 && element[1].kind == tokenSymbol && element[1].value == "(" {
+			continue
+		}
+		list = append(list, first.value)
+	}
+	return list, nil
+}
+
+// dataType returns the type name and whether it is an array type, the
+// modifiers such as the length and precision are ignored.
+func (p *ddlParser) dataType() (string, bool) {
+	var (
+		words []string
+		array bool
+	)
+	for !p.eof() && !p.isColumnOption() {
+		t := p.peek(0)
+		switch {
+		case t.kind == tokenSymbol && (t.value == "(" || t.value == "["):
+			array = array || t.value == "["
+			p.skipGroup()
+			continue
+		case t.kind == tokenSymbol && t.value == ".":
+			// the schema of the type is ignored.
+			words = nil
+		case t.kind == tokenIdent && t.value == "array":
+			array = true
+		case t.kind == tokenIdent, t.kind == tokenQuotedIdent:
+			words = append(words, t.value)
+		}
+		p.next()
+	}
+	return strings.Join(words, " "), array
+}
+
+// skipGroup skips the tokens until the closing bracket.
+func (p *ddlParser) skipGroup() {
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		if t.kind != tokenSymbol {
+			continue
+		}
+		switch t.value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		}
+		if depth == 0 {
+			return
+		}
+	}
+}
+
+// skipExpr skips the expression until the next column constraint, and
+// returns the skipped tokens.
+func (p *ddlParser) skipExpr() []token {
+	start, depth := p.pos, 0
+	for !p.eof() && (depth > 0 || !p.isColumnOption()) {
+		t := p.next()
+		switch {
+		case t.kind == tokenSymbol && (t.value == "(" || t.value == "["):
+			depth++
+		case t.kind == tokenSymbol && (t.value == ")" || t.value == "]"):
+			depth--
+		}
+	}
+	return p.tokens[start:p.pos]
+}
+
+// splitStatements splits the sql by semicolons, the semicolons in the
+// quoted strings, quoted identifiers and comments are ignored.
+func splitStatements(sql string) []string {
+	var (
+		list  []string
+		start int
+	)
+	for i := 0; i < len(sql); {
+		if n, _ := skipQuoted(sql, i); n > i {
+			i = n
+			continue
+		}
+		if sql[i] == ';' {
+			list = append(list, sql[start:i])
+			start = i + 1
+		}
+		i++
+	}
+	if len(strings.TrimSpace(sql[start:])) > 0 {
+		list = append(list, sql[start:])
+	}
+	return list
+}
+
+// skipQuoted returns the position after the quoted string, quoted identifier,
+// dollar quoted string or comment at i, the position is i if there is none.
+// It returns false if the quote is not terminated.
+func skipQuoted(sql string, i int) (int, bool) {
+	switch {
+	case strings.HasPrefix(sql[i:], "--"):
+		if n := strings.IndexByte(sql[i:], '\n'); n >= 0 {
+			return i + n + 1, true
+		}
+		return len(sql), true
+	case strings.HasPrefix(sql[i:], "/*"):
+		if n := strings.Index(sql[i+2:], "*/"); n >= 0 {
+			return i + 2 + n + 2, true
+		}
+		return len(sql), false
+	case sql[i] == '\'' || sql[i] == '"' || sql[i] == '`':
+		escape := isEscapeString(sql, i)
+		for j := i + 1; j < len(sql); j++ {
+			switch {
+			case escape && sql[j] == '\\':
+				j++
+			case sql[j] == sql[i] && j+1 < len(sql) && sql[j+1] == sql[i]:
+				j++
+			case sql[j] == sql[i]:
+				return j + 1, true
+			}
+		}
+		return len(sql), false
+	case sql[i] == '$':
+		tag := dollarTag(sql[i:])
+		if len(tag) == 0 {
+			return i, true
+		}
+		if n := strings.Index(sql[i+len(tag):], tag); n >= 0 {
+			return i + len(tag) + n + len(tag), true
+		}
+		return len(sql), false
+	default:
+		return i, true
+	}
+}
+
+// isEscapeString returns true if the string at i is an escape string, e.g. E'\n'.
+func isEscapeString(sql string, i int) bool {
+	if sql[i] != '\'' || i == 0 || (sql[i-1] != 'e' && sql[i-1] != 'E') {
+		return false
+	}
+	return i == 1 || !isIdentChar(sql[i-2])
+}
+
+// dollarTag returns the tag of the dollar quoted string, e.g. $$ or $body$.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1]
+		case c == '_' || isLetter(c) || (j > 1 && isDigit(c)):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// tokenize splits the statement into tokens, the comments are dropped. The
+// identifiers can be quoted by double quotes or backticks, and by square
+// brackets if brackets is true, otherwise the brackets are symbols, e.g. the
+// array types of postgres.
+func tokenize(sql string, brackets bool) ([]token, error) {
+	var list []token
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*"):
+			n, ok := skipQuoted(sql, i)
+			if !ok {
+				return nil, errorUnterminatedComment
+			}
+			i = n
+		case brackets && c == '[':
+			n := strings.IndexByte(sql[i:], ']')
+			if n < 0 {
+				return nil, errorUnterminatedString
+			}
+			list = append(list, token{kind: tokenQuotedIdent, value: sql[i+1 : i+n]})
+			i += n + 1
+		case c == '\'' || c == '"' || c == '`' || (c == '$' && len(dollarTag(sql[i:])) > 0):
+			n, ok := skipQuoted(sql, i)
+			if !ok {
+				return nil, errorUnterminatedString
+			}
+			tok := token{kind: tokenString}
+			switch {
+			case c == '"' || c == '`':
+				tok = token{kind: tokenQuotedIdent, value: unquote(sql[i+1:n-1], c, false)}
+			case c == '$':
+				tag := dollarTag(sql[i:])
+				tok.value = sql[i+len(tag) : n-len(tag)]
+			case isEscapeString(sql, i):
+				// the prefix E is tokenized as an identifier.
+				list = list[:len(list)-1]
+				tok.value = unquote(sql[i+1:n-1], c, true)
+			default:
+				tok.value = unquote(sql[i+1:n-1], c, false)
+			}
+			list = append(list, tok)
+			i = n
+		case isLetter(c) || c == '_' || c >= 0x80:
+			j := i + 1
+			for j < len(sql) && (isIdentChar(sql[j]) || sql[j] == '$') {
+				j++
+			}
+			list = append(list, token{kind: tokenIdent, value: strings.ToLower(sql[i:j])})
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			j := i + 1
+			for j < len(sql) && (isDigit(sql[j]) || sql[j] == '.') {
+				j++
+			}
+			list = append(list, token{kind: tokenNumber, value: sql[i:j]})
+			i = j
+		case strings.HasPrefix(sql[i:], "::"):
+			list = append(list, token{kind: tokenSymbol, value: "::"})
+			i += 2
+		default:
+			list = append(list, token{kind: tokenSymbol, value: string(c)})
+			i++
+		}
+	}
+	return list, nil
+}
+
+func unquote(s string, quote byte, escape bool) string {
+	q := string(quote)
+	s = strings.ReplaceAll(s, q+q, q)
+	if escape {
+		s = strings.ReplaceAll(s, `\'`, `'`)
+		s = strings.ReplaceAll(s, `\\`, `\`)
+	}
+	return s
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return isLetter(c) || isDigit(c) || c == '_' || c >= 0x80
+}
@@ -0,0 +1,337 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// Warning is a statement which is ignored by ParseDump.
+type Warning struct {
+	// Line is the line number of the statement, starting at 1.
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// dumpStmt is a statement in the dump and the line where it starts.
+type dumpStmt struct {
+	line int
+	text string
+}
+
+// ParseDump parses the mysql statements tolerantly, e.g. the output of
+// mysqldump --no-data. The schema statements are replayed as Migrator does,
+// the queries with the function name and the transactions are parsed as
+// Parse does, the other statements such as SET, LOCK TABLES, CREATE VIEW and
+// CREATE TRIGGER are ignored and reported as warnings, so are the statements
+// which can not be parsed and the tables without primary key.
+func ParseDump(sql string) (*spec.DXL, []Warning, error) {
+	var (
+		m        = NewMigrator()
+		queries  []string
+		warnings []Warning
+		// lines are the lines where the tables are created.
+		lines = map[string]int{}
+		// transaction is true between BEGIN and COMMIT, the queries in it
+		// belong to the function name of BEGIN.
+		transaction bool
+	)
+	warn := func(line int, format string, args ...any) {
+		warnings = append(warnings, Warning{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	stmts, err := splitDump(sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range stmts {
+		node, err := parseDumpStmt(s.text)
+		if err != nil {
+			warn(s.line, "ignored %s: %s", summary(s.text), syntaxError(s, err))
+			continue
+		}
+
+		switch stmt := node.(type) {
+		case *ast.CreateTableStmt:
+			if err := m.apply(node); err != nil {
+				warn(s.line, "ignored %s: %v", summary(s.text), err)
+				continue
+			}
+			lines[stmt.Table.Name.String()] = s.line
+		case *ast.AlterTableStmt, *ast.RenameTableStmt, *ast.CreateIndexStmt, *ast.DropIndexStmt:
+			if err := m.apply(node); err != nil {
+				warn(s.line, "ignored %s: %v", summary(s.text), err)
+			}
+		case *ast.DropTableStmt:
+			if stmt.IsView {
+				warn(s.line, "ignored %s", summary(s.text))
+				continue
+			}
+			if err := m.apply(node); err != nil {
+				warn(s.line, "ignored %s: %v", summary(s.text), err)
+			}
+		case *ast.BeginStmt:
+			transaction = true
+			queries = append(queries, s.text)
+		case *ast.CommitStmt:
+			transaction = false
+			queries = append(queries, s.text)
+		case *ast.InsertStmt, *ast.SelectStmt, *ast.DeleteStmt, *ast.UpdateStmt:
+			// the queries without function name are the rows of the tables.
+			if !transaction && !hasFuncName(s.text) {
+				warn(s.line, "ignored the query without function name: %s", summary(s.text))
+				continue
+			}
+			queries = append(queries, s.text)
+		default:
+			warn(s.line, "ignored %s", summary(s.text))
+		}
+	}
+
+	// the tables without primary key are not supported, e.g. the stand-in
+	// tables of the views in the dump of mysql 5.7.
+	for _, t := range append([]*spec.Table(nil), m.schema.tables...) {
+		if len(t.Constraint.PrimaryKey) != 1 {
+			warn(lines[t.Name], "ignored table %q which has no primary key", t.Name)
+			_ = m.dropTable(t.Name, true)
+		}
+	}
+
+	dxl, err := m.schema.dxl(queries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dxl, warnings, nil
+}
+
+// parseDumpStmt parses a single statement. The statement is parsed again
+// without the executable comments which are not at its beginning if it can
+// not be parsed, e.g. /*!80023 INVISIBLE */ of the columns in mysql 8.0.
+func parseDumpStmt(text string) (ast.StmtNode, error) {
+	stmtNodes, _, err := p.Parse(text, "", "")
+	if err != nil {
+		stripped := stripExecutableComments(text)
+		if stripped == text {
+			return nil, err
+		}
+		// reports the error of the original statement.
+		var e error
+		if stmtNodes, _, e = p.Parse(stripped, "", ""); e != nil {
+			return nil, err
+		}
+	}
+	if len(stmtNodes) != 1 {
+		return nil, errorUnsupportedStmt
+	}
+	return stmtNodes[0], nil
+}
+
+// syntaxError returns the error of the parser with the line number in the
+// dump instead of the one in the statement.
+func syntaxError(s dumpStmt, err error) string {
+	var line, column int
+	if _, e := fmt.Sscanf(err.Error(), "line %d column %d", &line, &column); e != nil {
+		return firstLine(err.Error())
+	}
+	offset, _ := stmtOffset(s.text)
+	line += s.line - strings.Count(s.text[:offset], "\n") - 1
+	return fmt.Sprintf("syntax error at line %d", line)
+}
+
+// splitDump splits the statements by the delimiter which is changed by the
+// DELIMITER command of the mysql client, the comments before a statement are
+// kept, e.g. -- fn: FindOne. The backslash escapes in the strings are
+// supported as mysql does.
+func splitDump(sql string) ([]dumpStmt, error) {
+	var (
+		list      []dumpStmt
+		delimiter = ";"
+		start     int
+		line      = 1
+		startLine = 1
+	)
+	flush := func(end int) {
+		text := sql[start:end]
+		if offset, ok := stmtOffset(text); ok {
+			list = append(list, dumpStmt{line: startLine + strings.Count(text[:offset], "\n"), text: text})
+		}
+	}
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == '#' || strings.HasPrefix(sql[i:], "-- ") || strings.HasPrefix(sql[i:], "--\t") ||
+			strings.HasPrefix(sql[i:], "--\n") || strings.HasPrefix(sql[i:], "--\r"):
+			n := strings.IndexByte(sql[i:], '\n')
+			if n < 0 {
+				i = len(sql)
+				continue
+			}
+			i += n
+		case strings.HasPrefix(sql[i:], "/*") && !strings.HasPrefix(sql[i:], "/*!"):
+			n := strings.Index(sql[i+2:], "*/")
+			if n < 0 {
+				return nil, fmt.Errorf("line %d: %w", line, errorUnterminatedComment)
+			}
+			line += strings.Count(sql[i:i+2+n+2], "\n")
+			i += 2 + n + 2
+		case c == '\'' || c == '"' || c == '`':
+			n, ok := skipMySQLQuoted(sql, i)
+			if !ok {
+				return nil, fmt.Errorf("line %d: %w", line, errorUnterminatedString)
+			}
+			line += strings.Count(sql[i:n], "\n")
+			i = n
+		case strings.HasPrefix(sql[i:], delimiter):
+			flush(i)
+			i += len(delimiter)
+			start, startLine = i, line
+		case isDelimiterCommand(sql, i, start):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			fields := strings.Fields(sql[i : i+end])
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: missing delimiter", line)
+			}
+			delimiter = fields[1]
+			i += end
+			start, startLine = i, line
+		default:
+			i++
+		}
+	}
+	flush(len(sql))
+	return list, nil
+}
+
+// isDelimiterCommand returns true if the DELIMITER command is at i, it must
+// be the first word of a statement.
+func isDelimiterCommand(sql string, i, start int) bool {
+	const command = "delimiter"
+	if len(sql)-i <= len(command) || !strings.EqualFold(sql[i:i+len(command)], command) {
+		return false
+	}
+	if c := sql[i+len(command)]; c != ' ' && c != '\t' {
+		return false
+	}
+	offset, ok := stmtOffset(sql[start:i])
+	return !ok || start+offset == i
+}
+
+// stmtOffset returns the offset of the first token in the statement which is
+// not a comment, it returns false if there is none.
+func stmtOffset(text string) (int, bool) {
+	for i := 0; i < len(text); {
+		switch {
+		case text[i] == ' ' || text[i] == '\t' || text[i] == '\r' || text[i] == '\n':
+			i++
+		case text[i] == '#' || strings.HasPrefix(text[i:], "--"):
+			n := strings.IndexByte(text[i:], '\n')
+			if n < 0 {
+				return 0, false
+			}
+			i += n
+		case strings.HasPrefix(text[i:], "/*") && !strings.HasPrefix(text[i:], "/*!"):
+			n := strings.Index(text[i+2:], "*/")
+			if n < 0 {
+				return 0, false
+			}
+			i += 2 + n + 2
+		default:
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// stripExecutableComments removes the executable comments, e.g.
+// /*!80016 DEFAULT ENCRYPTION='N' */, except the ones at the beginning of the
+// statement, which wrap the whole statement, e.g. /*!50001 CREATE VIEW ... */.
+func stripExecutableComments(text string) string {
+	offset, ok := stmtOffset(text)
+	if !ok {
+		return text
+	}
+	if strings.HasPrefix(text[offset:], "/*!") {
+		n := strings.Index(text[offset:], "*/")
+		if n < 0 {
+			return text
+		}
+		offset += n + 2
+	}
+
+	var b strings.Builder
+	b.WriteString(text[:offset])
+	for i := offset; i < len(text); {
+		if strings.HasPrefix(text[i:], "/*!") {
+			n := strings.Index(text[i:], "*/")
+			if n < 0 {
+				return text
+			}
+			b.WriteString(" ")
+			i += n + 2
+			continue
+		}
+		if n, _ := skipMySQLQuoted(text, i); n > i {
+			b.WriteString(text[i:n])
+			i = n
+			continue
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String()
+}
+
+// skipMySQLQuoted returns the position after the quoted string or identifier
+// at i, the backslash escapes in the strings are supported. It returns false if
+// the quote is not terminated.
+func skipMySQLQuoted(sql string, i int) (int, bool) {
+	quote := sql[i]
+	if quote != '\'' && quote != '"' && quote != '`' {
+		return i, true
+	}
+	for j := i + 1; j < len(sql); j++ {
+		switch {
+		case sql[j] == '\\' && quote != '`':
+			j++
+		case sql[j] == quote && j+1 < len(sql) && sql[j+1] == quote:
+			j++
+		case sql[j] == quote:
+			return j + 1, true
+		}
+	}
+	return len(sql), false
+}
+
+// summary returns the first line of the statement without the comments.
+func summary(text string) string {
+	const max = 64
+	if offset, ok := stmtOffset(text); ok {
+		text = text[offset:]
+	}
+	s := []rune(strings.TrimSpace(firstLine(text)))
+	if len(s) > max {
+		return string(s[:max]) + "..."
+	}
+	return string(s)
+}
+
+func firstLine(s string) string {
+	if n := strings.IndexByte(s, '\n'); n >= 0 {
+		return s[:n]
+	}
+	return s
+}
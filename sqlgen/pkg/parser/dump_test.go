@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+const mysql80Dump = "-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)\n" +
+	"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+	"/*!50503 SET NAMES utf8mb4 */;\n" +
+	"SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5';\n" +
+	"\n" +
+	"--\n" +
+	"-- Table structure for table `users`\n" +
+	"--\n" +
+	"\n" +
+	"DROP TABLE IF EXISTS `users`;\n" +
+	"/*!40101 SET @saved_cs_client     = @@character_set_client */;\n" +
+	"CREATE TABLE `users` (\n" +
+	"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `email` varchar(255) COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'it''s unique; lower case',\n" +
+	"  `note` varchar(255) NOT NULL DEFAULT 'a\\';b',\n" +
+	"  `secret` varchar(64) NOT NULL /*!80023 INVISIBLE */,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `uk_email` (`email`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci /*!80016 DEFAULT ENCRYPTION='N' */;\n" +
+	"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+	"DELIMITER ;;\n" +
+	"/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`localhost`*/ /*!50003 TRIGGER `users_bi` BEFORE INSERT ON `users` FOR EACH ROW BEGIN\n" +
+	"  SET NEW.email = LOWER(NEW.email);\n" +
+	"END */;;\n" +
+	"DELIMITER ;\n" +
+	"DROP TABLE IF EXISTS `user_emails`;\n" +
+	"/*!50001 DROP VIEW IF EXISTS `user_emails`*/;\n" +
+	"/*!50001 CREATE VIEW `user_emails` AS SELECT \n" +
+	" 1 AS `email`*/;\n" +
+	"-- fn: FindByEmail\n" +
+	"select * from users where email = ? limit 1;\n"
+
+const mysql57Dump = "-- MySQL dump 10.13  Distrib 5.7.44, for Linux (x86_64)\n" +
+	"/*!40101 SET NAMES utf8 */;\n" +
+	"DROP TABLE IF EXISTS `orders`;\n" +
+	"CREATE TABLE `orders` (\n" +
+	"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `code` varchar(32) CHARACTER SET utf8mb4 NOT NULL DEFAULT '' COMMENT '编号',\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `idx_code` (`code`)\n" +
+	") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8;\n" +
+	"LOCK TABLES `orders` WRITE;\n" +
+	"/*!40000 ALTER TABLE `orders` DISABLE KEYS */;\n" +
+	"INSERT INTO `orders` VALUES (1,'a;b');\n" +
+	"/*!40000 ALTER TABLE `orders` ENABLE KEYS */;\n" +
+	"UNLOCK TABLES;\n" +
+	"DROP TABLE IF EXISTS `order_codes`;\n" +
+	"/*!50001 CREATE TABLE `order_codes` (\n" +
+	"  `code` tinyint NOT NULL\n" +
+	") ENGINE=MyISAM */;\n" +
+	"/*!50001 DROP TABLE IF EXISTS `order_codes`*/;\n" +
+	"/*!50001 DROP VIEW IF EXISTS `order_codes`*/;\n" +
+	"/*!50001 CREATE ALGORITHM=UNDEFINED */\n" +
+	"/*!50001 VIEW `order_codes` AS select `orders`.`code` AS `code` from `orders` */;\n" +
+	"CREATE TABLE `logs` (`message` text);\n"
+
+func TestParseDump(t *testing.T) {
+	t.Run("mysql80", func(t *testing.T) {
+		dxl, warnings, err := ParseDump(mysql80Dump)
+		assert.NoError(t, err)
+		assert.Len(t, dxl.DDL, 1)
+		assert.Len(t, dxl.DML, 1)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, "users", table.Name)
+		assert.Equal(t, []string{"id", "email", "note", "secret"}, columnNames(table))
+		assert.Equal(t, map[string][]string{"uk_email": {"email"}}, table.Constraint.UniqueKey)
+		email, _ := table.GetColumnByName("email")
+		assert.Equal(t, "it's unique; lower case", email.Comment)
+
+		var lines []int
+		for _, w := range warnings {
+			lines = append(lines, w.Line)
+		}
+		// SET, the trigger, DROP VIEW and CREATE VIEW are ignored.
+		assert.Equal(t, []int{2, 3, 4, 11, 20, 22, 27, 28}, lines)
+		assert.Contains(t, warnings[5].Message, "syntax error at line 22")
+		assert.Equal(t, "ignored /*!50001 CREATE VIEW `user_emails` AS SELECT", warnings[7].Message)
+	})
+
+	t.Run("mysql57", func(t *testing.T) {
+		dxl, warnings, err := ParseDump(mysql57Dump)
+		assert.NoError(t, err)
+		assert.Len(t, dxl.DDL, 1)
+		assert.Len(t, dxl.DML, 0)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, "orders", table.Name)
+		assert.Equal(t, spec.Constraint{
+			PrimaryKey: map[string][]string{"": {"id"}},
+			UniqueKey:  map[string][]string{},
+			Index:      map[string][]string{"idx_code": {"code"}},
+		}, table.Constraint)
+
+		assert.Equal(t, []Warning{
+			{Line: 2, Message: "ignored /*!40101 SET NAMES utf8 */"},
+			{Line: 10, Message: "ignored LOCK TABLES `orders` WRITE"},
+			{Line: 12, Message: "ignored the query without function name: INSERT INTO `orders` VALUES (1,'a;b')"},
+			{Line: 14, Message: "ignored UNLOCK TABLES"},
+			{Line: 20, Message: "ignored /*!50001 DROP VIEW IF EXISTS `order_codes`*/"},
+			{Line: 21, Message: "ignored /*!50001 CREATE ALGORITHM=UNDEFINED */"},
+			{Line: 23, Message: `ignored table "logs" which has no primary key`},
+		}, warnings)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		dxl, warnings, err := ParseDump("CREATE TABLE foo (id bigint PRIMARY KEY, name varchar(255));\n" +
+			"-- fn: Rename\n" +
+			"begin;\n" +
+			"update foo set name = ? where id = ?;\n" +
+			"commit;")
+		assert.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.Len(t, dxl.DML, 1)
+		assert.IsType(t, &spec.Transaction{}, dxl.DML[0])
+	})
+
+	t.Run("unterminatedString", func(t *testing.T) {
+		_, _, err := ParseDump("CREATE TABLE foo (id bigint PRIMARY KEY);\nSET @a = 'foo;")
+		assert.ErrorContains(t, err, "line 2: "+errorUnterminatedString.Error())
+	})
+}
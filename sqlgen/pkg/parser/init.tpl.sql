@@ -1,20 +0,0 @@
--- fn: Insert
-insert into {{.insert_table}} ({{.insert_columns}}) values ({{.insert_values}});
-{{range .unique_indexes}}
--- fn: FindOneBy{{.UniqueNameJoin}}
-select {{.SelectColumns}} from {{.Table}} where {{.WhereClause}} limit 1;
-{{end}}
-{{range .general_indexes}}
-{{range .Items}}
--- fn: FindManyBy{{.IndexNameJoin}}
-select {{.SelectColumns}} from {{.Table}} where {{.WhereClause}};
-{{end}}
-{{end}}
-{{range .unique_indexes}}
--- fn: UpdateBy{{.UniqueNameJoin}}
-update {{.Table}} set {{.UpdateSet}} where {{.WhereClause}};
-{{end}}
-{{range .unique_indexes}}
--- fn: DeleteBy{{.UniqueNameJoin}}
-delete from {{.Table}} where {{.WhereClause}};
-{{end}}
\ No newline at end of file
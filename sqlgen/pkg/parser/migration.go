@@ -0,0 +1,410 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/ast"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// Migrator builds the schema by replaying the mysql migrations in order, the
+// following statements are applied, other statements such as INSERT and SET
+// are ignored:
+//   - CREATE TABLE, CREATE TABLE ... LIKE, DROP TABLE, RENAME TABLE
+//   - ALTER TABLE ADD/DROP/MODIFY/CHANGE/RENAME/ALTER COLUMN
+//   - ALTER TABLE ADD/DROP/RENAME INDEX, ADD/DROP PRIMARY KEY, RENAME TO
+//   - ALTER TABLE ADD/DROP FOREIGN KEY
+//   - CREATE INDEX, DROP INDEX
+type Migrator struct {
+	schema ddlSchema
+}
+
+// NewMigrator creates an instance for Migrator.
+func NewMigrator() *Migrator {
+	return &Migrator{}
+}
+
+// Apply applies the statements of a migration to the schema.
+func (m *Migrator) Apply(sql string) error {
+	stmtNodes, _, err := p.Parse(sql, "", "")
+	if err != nil {
+		return err
+	}
+
+	for _, node := range stmtNodes {
+		if err := m.apply(node); err != nil {
+			return errorNearBy(err, node.Text())
+		}
+	}
+	return nil
+}
+
+// DXL returns the final schema, it is validated as the schema parsed by Parse.
+func (m *Migrator) DXL() (*spec.DXL, error) {
+	return m.schema.dxl(nil)
+}
+
+func (m *Migrator) apply(node ast.StmtNode) error {
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt:
+		return m.createTable(stmt)
+	case *ast.DropTableStmt:
+		if stmt.IsView {
+			return nil
+		}
+		for _, t := range stmt.Tables {
+			if err := m.dropTable(t.Name.String(), stmt.IfExists); err != nil {
+				return err
+			}
+		}
+	case *ast.RenameTableStmt:
+		for _, t := range stmt.TableToTables {
+			if err := m.renameTable(t.OldTable.Name.String(), t.NewTable.Name.String()); err != nil {
+				return err
+			}
+		}
+	case *ast.CreateIndexStmt:
+		table, err := m.schema.table(stmt.Table.Name.String())
+		if err != nil {
+			return err
+		}
+		columns := parseColumnFromKeys(stmt.IndexPartSpecifications)
+		switch stmt.KeyType {
+		case ast.IndexKeyTypeUnique:
+			table.Constraint.AppendUniqueKey(stmt.IndexName, columns...)
+		case ast.IndexKeyTypeNone:
+			table.Constraint.AppendIndex(stmt.IndexName, columns...)
+		default:
+			// the fulltext and spatial indexes are not used by the queries.
+		}
+	case *ast.DropIndexStmt:
+		table, err := m.schema.table(stmt.Table.Name.String())
+		if err != nil {
+			return err
+		}
+		return dropIndex(table, stmt.IndexName, stmt.IfExists)
+	case *ast.AlterTableStmt:
+		table, err := m.schema.table(stmt.Table.Name.String())
+		if err != nil {
+			return err
+		}
+		for _, s := range stmt.Specs {
+			if err := m.alterTable(table, s); err != nil {
+				return err
+			}
+		}
+	default:
+		// ignores other statements, e.g. INSERT, SET.
+	}
+	return nil
+}
+
+func (m *Migrator) createTable(stmt *ast.CreateTableStmt) error {
+	name := stmt.Table.Name.String()
+	if t, _ := m.schema.table(name); t != nil {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("table %q already exists", name)
+	}
+
+	table := parseCreateTableStmt(stmt)
+	if stmt.ReferTable != nil {
+		refer, err := m.schema.table(stmt.ReferTable.Name.String())
+		if err != nil {
+			return err
+		}
+		table = cloneTable(refer)
+		table.Name = name
+	}
+	m.schema.tables = append(m.schema.tables, table)
+	return nil
+}
+
+func (m *Migrator) dropTable(name string, ifExists bool) error {
+	for i, t := range m.schema.tables {
+		if t.Name == name {
+			m.schema.tables = append(m.schema.tables[:i], m.schema.tables[i+1:]...)
+			return nil
+		}
+	}
+	if ifExists {
+		return nil
+	}
+	return fmt.Errorf("table %q is not defined", name)
+}
+
+func (m *Migrator) renameTable(from, to string) error {
+	table, err := m.schema.table(from)
+	if err != nil {
+		return err
+	}
+	if t, _ := m.schema.table(to); t != nil {
+		return fmt.Errorf("table %q already exists", to)
+	}
+	table.Name = to
+
+	// the foreign keys follow the renamed table as mysql does.
+	for _, t := range m.schema.tables {
+		for key, foreignKey := range t.Constraint.ForeignKey {
+			if foreignKey.ReferTable == from {
+				foreignKey.ReferTable = to
+				t.Constraint.ForeignKey[key] = foreignKey
+			}
+		}
+	}
+	return nil
+}
+
+// renameReferColumn renames the column referenced by the foreign keys.
+func (m *Migrator) renameReferColumn(table, from, to string) {
+	if from == to {
+		return
+	}
+	for _, t := range m.schema.tables {
+		for _, foreignKey := range t.Constraint.ForeignKey {
+			if foreignKey.ReferTable != table {
+				continue
+			}
+			for i, c := range foreignKey.ReferColumns {
+				if c == from {
+					foreignKey.ReferColumns[i] = to
+				}
+			}
+		}
+	}
+}
+
+func (m *Migrator) alterTable(table *spec.Table, s *ast.AlterTableSpec) error {
+	switch s.Tp {
+	case ast.AlterTableAddColumns:
+		for _, col := range s.NewColumns {
+			column, constraint := parseColumnDef(col)
+			if column == nil {
+				continue
+			}
+			if findColumn(table, column.Name) != nil {
+				if s.IfNotExists {
+					continue
+				}
+				return fmt.Errorf("column %q already exists in table %q", column.Name, table.Name)
+			}
+			if err := insertColumn(table, *column, s.Position); err != nil {
+				return err
+			}
+			table.Constraint.Merge(constraint)
+		}
+		for _, c := range s.NewConstraints {
+			table.Constraint.Merge(parseConstraint(c))
+		}
+	case ast.AlterTableAddConstraint:
+		constraint := s.Constraint
+		if constraint != nil && len(constraint.Name) == 0 {
+			if constraint.Tp == ast.ConstraintForeignKey {
+				constraint.Name = foreignKeyName(table.Name, &table.Constraint)
+			} else if columns := parseColumnFromKeys(constraint.Keys); len(columns) > 0 {
+				// mysql names the index by its first column.
+				constraint.Name = columns[0]
+			}
+		}
+		table.Constraint.Merge(parseConstraint(constraint))
+	case ast.AlterTableDropColumn:
+		return dropColumn(table, s.OldColumnName.Name.String())
+	case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+		name := s.NewColumns[0].Name.Name.String()
+		if s.OldColumnName != nil {
+			name = s.OldColumnName.Name.String()
+		}
+		column, constraint := parseColumnDef(s.NewColumns[0])
+		if err := replaceColumn(table, name, *column, s.Position); err != nil {
+			return err
+		}
+		table.Constraint.Merge(constraint)
+		m.renameReferColumn(table.Name, name, column.Name)
+	case ast.AlterTableRenameColumn:
+		old := findColumn(table, s.OldColumnName.Name.String())
+		if old == nil {
+			return fmt.Errorf("column %q is not defined in table %q", s.OldColumnName.Name.String(), table.Name)
+		}
+		column := *old
+		column.Name = s.NewColumnName.Name.String()
+		if err := replaceColumn(table, old.Name, column, nil); err != nil {
+			return err
+		}
+		m.renameReferColumn(table.Name, s.OldColumnName.Name.String(), column.Name)
+	case ast.AlterTableAlterColumn:
+		column := findColumn(table, s.NewColumns[0].Name.Name.String())
+		if column == nil {
+			return fmt.Errorf("column %q is not defined in table %q", s.NewColumns[0].Name.Name.String(), table.Name)
+		}
+		// SET DEFAULT has the default value option, DROP DEFAULT has none.
+		column.HasDefaultValue = len(s.NewColumns[0].Options) > 0
+	case ast.AlterTableDropPrimaryKey:
+		table.Constraint.PrimaryKey = map[string][]string{}
+	case ast.AlterTableDropIndex:
+		return dropIndex(table, s.Name, s.IfExists)
+	case ast.AlterTableDropForeignKey:
+		if _, ok := table.Constraint.ForeignKey[s.Name]; !ok {
+			if s.IfExists {
+				return nil
+			}
+			return fmt.Errorf("foreign key %q is not defined in table %q", s.Name, table.Name)
+		}
+		delete(table.Constraint.ForeignKey, s.Name)
+	case ast.AlterTableRenameIndex:
+		renameIndex(table, s.FromKey.String(), s.ToKey.String())
+	case ast.AlterTableRenameTable:
+		return m.renameTable(table.Name, s.NewTable.Name.String())
+	default:
+		// ignores other actions, e.g. ENGINE, COMMENT, PARTITION.
+	}
+	return nil
+}
+
+// insertColumn inserts the column at the position, e.g. FIRST, AFTER foo, the
+// column is appended if the position is not specified.
+func insertColumn(table *spec.Table, column spec.Column, position *ast.ColumnPosition) error {
+	index := len(table.Columns)
+	if position != nil {
+		switch position.Tp {
+		case ast.ColumnPositionFirst:
+			index = 0
+		case ast.ColumnPositionAfter:
+			index = -1
+			for i, c := range table.Columns {
+				if c.Name == position.RelativeColumn.Name.String() {
+					index = i + 1
+				}
+			}
+			if index < 0 {
+				return fmt.Errorf("column %q is not defined in table %q", position.RelativeColumn.Name.String(), table.Name)
+			}
+		}
+	}
+
+	table.Columns = append(table.Columns, spec.Column{})
+	copy(table.Columns[index+1:], table.Columns[index:])
+	table.Columns[index] = column
+	return nil
+}
+
+// replaceColumn replaces the column by name, the column is renamed in the
+// constraints if its name is changed.
+func replaceColumn(table *spec.Table, name string, column spec.Column, position *ast.ColumnPosition) error {
+	index := -1
+	for i, c := range table.Columns {
+		if c.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("column %q is not defined in table %q", name, table.Name)
+	}
+
+	if position == nil || position.Tp == ast.ColumnPositionNone {
+		table.Columns[index] = column
+	} else {
+		table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
+		if err := insertColumn(table, column, position); err != nil {
+			return err
+		}
+	}
+
+	if column.Name != name {
+		for _, constraint := range constraints(table) {
+			for _, columns := range constraint {
+				for i, c := range columns {
+					if c == name {
+						columns[i] = column.Name
+					}
+				}
+			}
+		}
+		for _, foreignKey := range table.Constraint.ForeignKey {
+			for i, c := range foreignKey.Columns {
+				if c == name {
+					foreignKey.Columns[i] = column.Name
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dropColumn drops the column, and removes it from the constraints, the
+// constraints without columns and the foreign keys on it are dropped too.
+func dropColumn(table *spec.Table, name string) error {
+	index := -1
+	for i, c := range table.Columns {
+		if c.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("column %q is not defined in table %q", name, table.Name)
+	}
+	table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
+
+	for _, constraint := range constraints(table) {
+		for key, columns := range constraint {
+			var list []string
+			for _, c := range columns {
+				if c != name {
+					list = append(list, c)
+				}
+			}
+			if len(list) == 0 {
+				delete(constraint, key)
+				continue
+			}
+			constraint[key] = list
+		}
+	}
+	for key, foreignKey := range table.Constraint.ForeignKey {
+		for _, c := range foreignKey.Columns {
+			if c == name {
+				delete(table.Constraint.ForeignKey, key)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func dropIndex(table *spec.Table, name string, ifExists bool) error {
+	for _, constraint := range []map[string][]string{table.Constraint.UniqueKey, table.Constraint.Index} {
+		if _, ok := constraint[name]; ok {
+			delete(constraint, name)
+			return nil
+		}
+	}
+	if ifExists {
+		return nil
+	}
+	return fmt.Errorf("index %q is not defined in table %q", name, table.Name)
+}
+
+func renameIndex(table *spec.Table, from, to string) {
+	for _, constraint := range []map[string][]string{table.Constraint.UniqueKey, table.Constraint.Index} {
+		if columns, ok := constraint[from]; ok {
+			delete(constraint, from)
+			constraint[to] = columns
+		}
+	}
+}
+
+func constraints(table *spec.Table) []map[string][]string {
+	return []map[string][]string{table.Constraint.PrimaryKey, table.Constraint.UniqueKey, table.Constraint.Index}
+}
+
+// cloneTable clones the table for CREATE TABLE ... LIKE, the foreign keys are
+// not cloned as mysql does.
+func cloneTable(table *spec.Table) *spec.Table {
+	clone := *table
+	clone.Columns = append(spec.Columns{}, table.Columns...)
+	clone.Constraint = *spec.NewConstraint()
+	clone.Constraint.Merge(&table.Constraint)
+	clone.Constraint.ForeignKey = nil
+	return &clone
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+func columnNames(table *spec.Table) []string {
+	var list []string
+	for _, c := range table.Columns {
+		list = append(list, c.Name)
+	}
+	return list
+}
+
+func TestMigrator(t *testing.T) {
+	t.Run("replay", func(t *testing.T) {
+		m := NewMigrator()
+		for _, sql := range []string{
+			"CREATE TABLE `user` (`id` bigint NOT NULL AUTO_INCREMENT, `name` varchar(255) NOT NULL, `nick` varchar(64), PRIMARY KEY (`id`)) ENGINE=InnoDB;\n" +
+				"CREATE TABLE `tmp` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n" +
+				"INSERT INTO `user` (`name`) VALUES ('admin');",
+			"ALTER TABLE `user` ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '' AFTER `id`, ADD UNIQUE INDEX `uk_email` (`email`);\n" +
+				"ALTER TABLE `user` ADD COLUMN `tenant_id` bigint NOT NULL FIRST;\n" +
+				"CREATE INDEX `idx_name` ON `user` (`name`);",
+			"ALTER TABLE `user` DROP COLUMN `nick`, MODIFY COLUMN `name` varchar(128) COMMENT 'the name';\n" +
+				"ALTER TABLE `user` CHANGE `email` `mail` varchar(255) NOT NULL;\n" +
+				"ALTER TABLE `user` RENAME COLUMN `name` TO `full_name`, ADD INDEX (`tenant_id`);\n" +
+				"ALTER TABLE `user` RENAME INDEX `idx_name` TO `idx_full_name`;",
+			"RENAME TABLE `user` TO `account`;\n" +
+				"DROP TABLE `tmp`;\n" +
+				"DROP TABLE IF EXISTS `missing`;",
+		} {
+			assert.NoError(t, m.Apply(sql))
+		}
+
+		dxl, err := m.DXL()
+		assert.NoError(t, err)
+		assert.Len(t, dxl.DDL, 1)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, "account", table.Name)
+		assert.Equal(t, []string{"tenant_id", "id", "mail", "full_name"}, columnNames(table))
+		assert.Equal(t, map[string][]string{"uk_email": {"mail"}}, table.Constraint.UniqueKey)
+		assert.Equal(t, map[string][]string{"idx_full_name": {"full_name"}, "tenant_id": {"tenant_id"}}, table.Constraint.Index)
+
+		name, _ := table.GetColumnByName("full_name")
+		assert.Equal(t, "the name", name.Comment)
+		assert.False(t, name.NotNull)
+		assert.Equal(t, mysql.TypeVarchar, name.TP)
+		mail, _ := table.GetColumnByName("mail")
+		assert.True(t, mail.NotNull)
+		assert.False(t, mail.HasDefaultValue)
+	})
+
+	t.Run("dropColumnInIndex", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE foo (id bigint PRIMARY KEY, a int, b int, KEY idx_a_b (a, b), KEY idx_b (b));\n"+
+			"ALTER TABLE foo DROP COLUMN b;"))
+		dxl, err := m.DXL()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"idx_a_b": {"a"}}, dxl.DDL[0].Table.Constraint.Index)
+	})
+
+	t.Run("primaryKey", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE foo (id bigint, code varchar(32), PRIMARY KEY (id));\n"+
+			"ALTER TABLE foo DROP PRIMARY KEY, ADD PRIMARY KEY (code);\n"+
+			"ALTER TABLE foo ALTER COLUMN id SET DEFAULT 0;\n"+
+			"CREATE TABLE bar LIKE foo;"))
+		dxl, err := m.DXL()
+		assert.NoError(t, err)
+		assert.Len(t, dxl.DDL, 2)
+		for _, ddl := range dxl.DDL {
+			for _, columns := range ddl.Table.Constraint.PrimaryKey {
+				assert.Equal(t, []string{"code"}, columns)
+			}
+			id, _ := ddl.Table.GetColumnByName("id")
+			assert.True(t, id.HasDefaultValue)
+		}
+	})
+
+	t.Run("foreignKey", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE user (id bigint PRIMARY KEY);\n"+
+			"CREATE TABLE orders (id bigint PRIMARY KEY, uid bigint, buyer_id bigint, CONSTRAINT fk_uid FOREIGN KEY (uid) REFERENCES user (id));\n"+
+			"ALTER TABLE orders ADD FOREIGN KEY (buyer_id) REFERENCES user (id);\n"+
+			"CREATE TABLE orders_copy LIKE orders;\n"+
+			"ALTER TABLE orders CHANGE uid user_id bigint;\n"+
+			"ALTER TABLE user RENAME COLUMN id TO user_id;\n"+
+			"RENAME TABLE user TO users;"))
+		dxl, err := m.DXL()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]spec.ForeignKey{
+			"fk_uid":        {Columns: []string{"user_id"}, ReferTable: "users", ReferColumns: []string{"user_id"}},
+			"orders_ibfk_1": {Columns: []string{"buyer_id"}, ReferTable: "users", ReferColumns: []string{"user_id"}},
+		}, dxl.DDL[1].Table.Constraint.ForeignKey)
+		assert.Empty(t, dxl.DDL[2].Table.Constraint.ForeignKey)
+
+		assert.NoError(t, m.Apply("ALTER TABLE orders DROP FOREIGN KEY fk_uid;\nALTER TABLE orders DROP COLUMN buyer_id;"))
+		dxl, err = m.DXL()
+		assert.NoError(t, err)
+		assert.Empty(t, dxl.DDL[1].Table.Constraint.ForeignKey)
+		assert.ErrorContains(t, m.Apply("ALTER TABLE orders DROP FOREIGN KEY fk_uid;"), `foreign key "fk_uid" is not defined`)
+	})
+
+	t.Run("undefinedTable", func(t *testing.T) {
+		assert.ErrorContains(t, NewMigrator().Apply("ALTER TABLE foo ADD COLUMN bar int;"), `table "foo" is not defined`)
+	})
+
+	t.Run("undefinedColumn", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE foo (id bigint PRIMARY KEY);"))
+		assert.ErrorContains(t, m.Apply("ALTER TABLE foo DROP COLUMN bar;"), `column "bar" is not defined`)
+		assert.ErrorContains(t, m.Apply("ALTER TABLE foo ADD COLUMN bar int AFTER baz;"), `column "baz" is not defined`)
+		assert.ErrorContains(t, m.Apply("DROP INDEX idx_bar ON foo;"), `index "idx_bar" is not defined`)
+	})
+
+	t.Run("duplicateTable", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE foo (id bigint PRIMARY KEY);\nCREATE TABLE IF NOT EXISTS foo (id int PRIMARY KEY);"))
+		assert.ErrorContains(t, m.Apply("CREATE TABLE foo (id bigint PRIMARY KEY);"), `table "foo" already exists`)
+	})
+
+	t.Run("missingPrimaryKey", func(t *testing.T) {
+		m := NewMigrator()
+		assert.NoError(t, m.Apply("CREATE TABLE foo (id bigint PRIMARY KEY);\nALTER TABLE foo DROP PRIMARY KEY;"))
+		_, err := m.DXL()
+		assert.Error(t, err)
+	})
+}
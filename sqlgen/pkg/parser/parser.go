@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/xyzbit/codegen/pkg/buffer"
 	"github.com/xyzbit/codegen/pkg/set"
@@ -60,6 +61,19 @@ func init() {
 
 // Parse parses a SQL statement string and returns a spec.DXL.
 func Parse(sql string) (*spec.DXL, error) {
+	ret, err := parse(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func parse(sql string) (*spec.DXL, error) {
 	// fmt.Printf("sql %s \n", sql)
 	stmtNodes, _, err := p.Parse(sql, "", "")
 	if err != nil {
@@ -103,10 +117,6 @@ func Parse(sql string) (*spec.DXL, error) {
 		}
 	}
 
-	if err = ret.Validate(); err != nil {
-		return nil, err
-	}
-
 	return &ret, nil
 }
 
@@ -314,6 +324,7 @@ func parseColumnDef(col *ast.ColumnDef) (*spec.Column, *spec.Constraint) {
 	if tp != nil {
 		column.Unsigned = mysql.HasUnsignedFlag(tp.Flag)
 		column.TP = tp.Tp
+		column.DBType = strings.ToLower(tp.InfoSchemaStr())
 	}
 
 	column.Name = col.Name.String()
@@ -365,6 +376,20 @@ func parseConstraint(constraint *ast.Constraint) *spec.Constraint {
 		ret.AppendIndex(key, columns...)
 	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
 		ret.AppendUniqueKey(key, columns...)
+	case ast.ConstraintForeignKey:
+		refer := constraint.Refer
+		if refer == nil || refer.Table == nil {
+			return ret
+		}
+		referColumns := parseColumnFromKeys(refer.IndexPartSpecifications)
+		if len(referColumns) != len(columns) {
+			return ret
+		}
+		ret.AppendForeignKey(key, spec.ForeignKey{
+			Columns:      columns,
+			ReferTable:   refer.Table.Name.String(),
+			ReferColumns: referColumns,
+		})
 	default:
 		// ignore other constraints
 	}
@@ -372,6 +397,17 @@ func parseConstraint(constraint *ast.Constraint) *spec.Constraint {
 	return ret
 }
 
+// foreignKeyName returns the name of the unnamed foreign key as mysql does,
+// e.g. orders_ibfk_1.
+func foreignKeyName(table string, constraint *spec.Constraint) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_ibfk_%d", table, i)
+		if _, ok := constraint.ForeignKey[name]; !ok {
+			return name
+		}
+	}
+}
+
 func parseColumnFromKeys(keys []*ast.IndexPartSpecification) []string {
 	columnSet := set.From()
 	for _, key := range keys {
@@ -434,6 +470,9 @@ func parseCreateTableStmt(stmt *ast.CreateTableStmt) *spec.Table {
 	}
 
 	for _, c := range stmt.Constraints {
+		if c.Tp == ast.ConstraintForeignKey && len(c.Name) == 0 {
+			c.Name = foreignKeyName(table.Name, constraint)
+		}
 		constraint.Merge(parseConstraint(c))
 	}
 
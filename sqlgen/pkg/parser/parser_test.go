@@ -31,6 +31,17 @@ func TestParse(t *testing.T) {
 		assert.ErrorIs(t, err, errorUnsupportedStmt)
 	})
 
+	t.Run("multipleTableTransaction", func(t *testing.T) {
+		_, err := Parse(`-- fn: foo
+begin;
+-- fn: bar
+delete from bar;
+-- fn: baz
+delete from baz;
+commit;`)
+		assert.NotNil(t, err)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		dxl, err := Parse(testSql)
 		assert.Nil(t, err)
@@ -38,6 +49,35 @@ func TestParse(t *testing.T) {
 		_, err = spec.From(dxl)
 		assert.Nil(t, err)
 	})
+
+	t.Run("foreignKey", func(t *testing.T) {
+		dxl, err := Parse("CREATE TABLE users (id bigint PRIMARY KEY);\n" +
+			"CREATE TABLE orders (\n" +
+			"  id bigint PRIMARY KEY,\n" +
+			"  user_id bigint NOT NULL,\n" +
+			"  seller_id bigint NOT NULL,\n" +
+			"  KEY idx_user_id (user_id),\n" +
+			"  CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,\n" +
+			"  FOREIGN KEY (seller_id) REFERENCES users (id)\n" +
+			");")
+		assert.Nil(t, err)
+		assert.Nil(t, dxl.DDL[0].Table.Constraint.ForeignKey)
+		assert.Equal(t, map[string]spec.ForeignKey{
+			"fk_orders_user": {Columns: []string{"user_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+			"orders_ibfk_1":  {Columns: []string{"seller_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+		}, dxl.DDL[1].Table.Constraint.ForeignKey)
+		assert.Equal(t, map[string][]string{"idx_user_id": {"user_id"}}, dxl.DDL[1].Table.Constraint.Index)
+	})
+
+	t.Run("dbType", func(t *testing.T) {
+		dxl, err := Parse("CREATE TABLE foo (id BIGINT(20) UNSIGNED NOT NULL PRIMARY KEY, paid TINYINT(1) NOT NULL, extra JSON, name VARCHAR(64));")
+		assert.Nil(t, err)
+		var dbTypes []string
+		for _, c := range dxl.DDL[0].Table.Columns {
+			dbTypes = append(dbTypes, c.DBType)
+		}
+		assert.Equal(t, []string{"bigint(20) unsigned", "tinyint(1)", "json", "varchar(64)"}, dbTypes)
+	})
 }
 
 func Test_parseDML(t *testing.T) {
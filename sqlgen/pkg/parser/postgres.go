@@ -0,0 +1,466 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// pgStr2Type maps the postgres type names to the column types, the types
+// which are not listed here, e.g. the enum types created by CREATE TYPE, are strings.
+var pgStr2Type = map[string]byte{
+	"smallint":                    mysql.TypeShort,
+	"int2":                        mysql.TypeShort,
+	"smallserial":                 mysql.TypeShort,
+	"serial2":                     mysql.TypeShort,
+	"integer":                     mysql.TypeLong,
+	"int":                         mysql.TypeLong,
+	"int4":                        mysql.TypeLong,
+	"serial":                      mysql.TypeLong,
+	"serial4":                     mysql.TypeLong,
+	"bigint":                      mysql.TypeLonglong,
+	"int8":                        mysql.TypeLonglong,
+	"bigserial":                   mysql.TypeLonglong,
+	"serial8":                     mysql.TypeLonglong,
+	"real":                        mysql.TypeFloat,
+	"float4":                      mysql.TypeFloat,
+	"double precision":            mysql.TypeDouble,
+	"float8":                      mysql.TypeDouble,
+	"float":                       mysql.TypeDouble,
+	"numeric":                     mysql.TypeNewDecimal,
+	"decimal":                     mysql.TypeNewDecimal,
+	"boolean":                     spec.TypeBool,
+	"bool":                        spec.TypeBool,
+	"character varying":           mysql.TypeVarchar,
+	"varchar":                     mysql.TypeVarchar,
+	"character":                   mysql.TypeString,
+	"char":                        mysql.TypeString,
+	"bpchar":                      mysql.TypeString,
+	"uuid":                        mysql.TypeString,
+	"text":                        mysql.TypeBlob,
+	"citext":                      mysql.TypeBlob,
+	"xml":                         mysql.TypeBlob,
+	"bytea":                       mysql.TypeBlob,
+	"json":                        mysql.TypeJSON,
+	"jsonb":                       mysql.TypeJSON,
+	"date":                        mysql.TypeDate,
+	"time":                        mysql.TypeDuration,
+	"time without time zone":      mysql.TypeDuration,
+	"time with time zone":         mysql.TypeDuration,
+	"timetz":                      mysql.TypeDuration,
+	"timestamp":                   mysql.TypeTimestamp,
+	"timestamp without time zone": mysql.TypeTimestamp,
+	"timestamp with time zone":    mysql.TypeTimestamp,
+	"timestamptz":                 mysql.TypeTimestamp,
+}
+
+// pgSerialTypes are the types which create a sequence as the default value of the column.
+var pgSerialTypes = map[string]struct{}{
+	"smallserial": {},
+	"serial2":     {},
+	"serial":      {},
+	"serial4":     {},
+	"bigserial":   {},
+	"serial8":     {},
+}
+
+// pgTypeMapper returns the column type of the postgres type name, and whether
+// the column is auto increment.
+func pgTypeMapper(tp string, array bool) (byte, bool) {
+	l := strings.ToLower(tp)
+	ret, ok := pgStr2Type[l]
+	if !ok {
+		ret = mysql.TypeVarchar
+	}
+	if array {
+		return pgArrayType(ret), false
+	}
+	_, serial := pgSerialTypes[l]
+	return ret, serial
+}
+
+// pgColumnType returns the column type of the type returned by format_type,
+// e.g. character varying(255), timestamp(3) with time zone, text[].
+func pgColumnType(tp string) (byte, error) {
+	tokens, err := tokenize(tp, false)
+	if err != nil {
+		return 0, err
+	}
+	name, array := newPgParser(tokens).dataType()
+	if len(name) == 0 {
+		return 0, fmt.Errorf("unsupported type:%s", tp)
+	}
+	ret, _ := pgTypeMapper(name, array)
+	return ret, nil
+}
+
+func pgArrayType(tp byte) byte {
+	switch tp {
+	case mysql.TypeShort, mysql.TypeLong, mysql.TypeLonglong:
+		return spec.TypeInt64Array
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return spec.TypeFloat64Array
+	case spec.TypeBool:
+		return spec.TypeBoolArray
+	default:
+		return spec.TypeStringArray
+	}
+}
+
+// ParsePostgres parses the postgres DDL and returns a spec.DXL, CREATE TABLE,
+// CREATE INDEX, COMMENT ON COLUMN and the constraints added by ALTER TABLE
+// are supported, e.g. the output of pg_dump --schema-only, other statements
+// such as SET, CREATE EXTENSION and CREATE SEQUENCE are ignored.
+// The queries are parsed by Parse, so they must be written in the syntax
+// which is compatible with mysql, e.g. use ? as the placeholder.
+func ParsePostgres(sql string) (*spec.DXL, error) {
+	var (
+		schema  pgSchema
+		queries []string
+	)
+	for _, text := range splitStatements(sql) {
+		tokens, err := tokenize(text, false)
+		if err != nil {
+			return nil, errorNearBy(err, strings.TrimSpace(text))
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		p := newPgParser(tokens)
+		switch {
+		case p.isKeyword("create"):
+			err = schema.create(p)
+		case p.isKeyword("alter", "table"):
+			err = schema.alterTable(p)
+		case p.isKeyword("comment", "on"):
+			err = schema.comment(p)
+		case p.isQuery():
+			queries = append(queries, text)
+		default:
+			// ignores other statements
+		}
+		if err != nil {
+			return nil, errorNearBy(err, strings.TrimSpace(text))
+		}
+	}
+
+	return schema.dxl(queries)
+}
+
+type pgSchema struct {
+	ddlSchema
+}
+
+func (s *pgSchema) create(p *ddlParser) error {
+	p.acceptKeyword("create")
+	p.acceptKeyword("or", "replace")
+	unique := p.acceptKeyword("unique")
+	if p.isKeyword("index") {
+		return s.createIndex(p, unique)
+	}
+	for _, modifier := range []string{"global", "local", "temp", "temporary", "unlogged"} {
+		p.acceptKeyword(modifier)
+	}
+	if !p.acceptKeyword("table") {
+		// ignores other objects, e.g. CREATE SEQUENCE, CREATE TYPE.
+		return nil
+	}
+
+	p.acceptKeyword("if", "not", "exists")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if p.isKeyword("of") || p.isKeyword("partition", "of") || p.isKeyword("as") {
+		// the partitions, typed tables and CREATE TABLE AS are not supported.
+		s.skip(name)
+		return nil
+	}
+	if t, _ := s.table(name); t != nil {
+		return fmt.Errorf("table %q is defined more than once", name)
+	}
+
+	elements, err := p.group()
+	if err != nil {
+		return err
+	}
+	table := &spec.Table{Name: name, Constraint: *spec.NewConstraint()}
+	for _, element := range elements {
+		if err := tableElement(table, p.sub(element)); err != nil {
+			return err
+		}
+	}
+
+	s.tables = append(s.tables, table)
+	return nil
+}
+
+func tableElement(table *spec.Table, p *ddlParser) error {
+	var name string
+	if p.acceptKeyword("constraint") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("primary", "key"):
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		appendPrimaryKey(table, name, columns...)
+	case p.acceptKeyword("unique"):
+		p.acceptKeyword("nulls", "not", "distinct")
+		p.acceptKeyword("nulls", "distinct")
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		appendUniqueKey(table, name, columns...)
+	case p.acceptKeyword("foreign", "key"):
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		if !p.acceptKeyword("references") {
+			return fmt.Errorf("missing references of foreign key %q", name)
+		}
+		return references(table, p, name, columns...)
+	case len(name) > 0, p.isKeyword("check"), p.isKeyword("exclude"), p.isKeyword("like"):
+		// ignore other constraints
+	default:
+		column, err := columnDef(table, p)
+		if err != nil {
+			return err
+		}
+		table.Columns = append(table.Columns, *column)
+	}
+	return nil
+}
+
+// pgColumnOptions are the keywords which start a column constraint.
+var pgColumnOptions = map[string]struct{}{
+	"constraint": {},
+	"not":        {},
+	"null":       {},
+	"default":    {},
+	"primary":    {},
+	"unique":     {},
+	"check":      {},
+	"references": {},
+	"generated":  {},
+	"collate":    {},
+}
+
+func newPgParser(tokens []token) *ddlParser {
+	return &ddlParser{tokens: tokens, options: pgColumnOptions}
+}
+
+// isSequence returns whether the default value is a sequence, e.g. nextval('foo_id_seq'::regclass).
+func isSequence(expr []token) bool {
+	for _, t := range expr {
+		if t.kind == tokenIdent && t.value == "nextval" {
+			return true
+		}
+	}
+	return false
+}
+
+func columnDef(table *spec.Table, p *ddlParser) (*spec.Column, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	var column spec.Column
+	column.Name = name
+	tp, array := p.dataType()
+	if len(tp) == 0 {
+		return nil, fmt.Errorf("missing type of column %q", name)
+	}
+	column.TP, column.AutoIncrement = pgTypeMapper(tp, array)
+	column.DBType = strings.ToLower(tp)
+	if array {
+		column.DBType += "[]"
+	}
+	if column.AutoIncrement {
+		column.NotNull = true
+		column.HasDefaultValue = true
+	}
+
+	for !p.eof() {
+		var key string
+		if p.acceptKeyword("constraint") {
+			if key, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case p.acceptKeyword("not", "null"):
+			column.NotNull = true
+		case p.acceptKeyword("null"):
+		case p.acceptKeyword("default"):
+			column.HasDefaultValue = true
+			if isSequence(p.skipExpr()) {
+				column.AutoIncrement = true
+			}
+		case p.acceptKeyword("primary", "key"):
+			column.NotNull = true
+			appendPrimaryKey(table, key, name)
+		case p.acceptKeyword("unique"):
+			p.acceptKeyword("nulls", "not", "distinct")
+			p.acceptKeyword("nulls", "distinct")
+			appendUniqueKey(table, key, name)
+		case p.acceptKeyword("references"):
+			if err := references(table, p, key, name); err != nil {
+				return nil, err
+			}
+		case p.acceptKeyword("generated"):
+			identity := generated(p)
+			column.HasDefaultValue = true
+			if identity {
+				column.AutoIncrement = true
+				column.NotNull = true
+			}
+		default:
+			// ignore other constraints, e.g. CHECK, COLLATE.
+			p.next()
+			p.skipExpr()
+		}
+	}
+
+	return &column, nil
+}
+
+// generated skips the GENERATED clause and returns whether it is an identity column.
+func generated(p *ddlParser) bool {
+	identity := false
+	for !p.eof() && !p.isColumnOption() {
+		if p.acceptKeyword("identity") {
+			identity = true
+			continue
+		}
+		if p.isSymbol("(") {
+			if _, err := p.group(); err != nil {
+				return identity
+			}
+			continue
+		}
+		p.next()
+	}
+	return identity
+}
+
+func (s *pgSchema) alterTable(p *ddlParser) error {
+	p.acceptKeyword("alter", "table")
+	p.acceptKeyword("if", "exists")
+	p.acceptKeyword("only")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table, err := s.table(name)
+	if err != nil || table == nil {
+		return err
+	}
+
+	for _, action := range p.elements() {
+		if err := alterTableAction(table, p.sub(action)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func alterTableAction(table *spec.Table, p *ddlParser) error {
+	switch {
+	case p.acceptKeyword("add"):
+		if p.isKeyword("constraint") || p.isKeyword("primary") || p.isKeyword("unique") ||
+			p.isKeyword("check") || p.isKeyword("foreign") || p.isKeyword("exclude") {
+			return tableElement(table, p)
+		}
+		p.acceptKeyword("column")
+		p.acceptKeyword("if", "not", "exists")
+		column, err := columnDef(table, p)
+		if err != nil {
+			return err
+		}
+		if findColumn(table, column.Name) == nil {
+			table.Columns = append(table.Columns, *column)
+		}
+	case p.acceptKeyword("alter"):
+		p.acceptKeyword("column")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		column := findColumn(table, name)
+		if column == nil {
+			return fmt.Errorf("column %q is not defined in table %q", name, table.Name)
+		}
+		switch {
+		case p.acceptKeyword("set", "default"):
+			column.HasDefaultValue = true
+			if isSequence(p.skipExpr()) {
+				column.AutoIncrement = true
+			}
+		case p.acceptKeyword("drop", "default"):
+			column.HasDefaultValue = false
+			column.AutoIncrement = false
+		case p.acceptKeyword("set", "not", "null"):
+			column.NotNull = true
+		case p.acceptKeyword("drop", "not", "null"):
+			column.NotNull = false
+		case p.acceptKeyword("add", "generated"):
+			if generated(p) {
+				column.AutoIncrement = true
+				column.HasDefaultValue = true
+			}
+		default:
+			// ignore other actions, e.g. SET STATISTICS.
+		}
+	default:
+		// ignore other actions, e.g. OWNER TO.
+	}
+	return nil
+}
+
+func (s *pgSchema) comment(p *ddlParser) error {
+	p.acceptKeyword("comment", "on")
+	if !p.acceptKeyword("column") {
+		// ignores the comments of other objects, e.g. COMMENT ON TABLE.
+		return nil
+	}
+
+	names, err := p.qualifiedNames()
+	if err != nil {
+		return err
+	}
+	if len(names) < 2 {
+		return fmt.Errorf("missing table name of column %q", names[0])
+	}
+	table, err := s.table(names[len(names)-2])
+	if err != nil || table == nil {
+		return err
+	}
+	column := findColumn(table, names[len(names)-1])
+	if column == nil {
+		return fmt.Errorf("column %q is not defined in table %q", names[len(names)-1], table.Name)
+	}
+	if !p.acceptKeyword("is") {
+		return errorUnsupportedStmt
+	}
+	if t := p.next(); t.kind == tokenString {
+		column.Comment = t.value
+	} else {
+		column.Comment = ""
+	}
+	return nil
+}
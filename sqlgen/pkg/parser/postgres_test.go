@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+func TestParsePostgres(t *testing.T) {
+	t.Run("types", func(t *testing.T) {
+		dxl, err := ParsePostgres(`CREATE TABLE public."user" (
+    id bigserial PRIMARY KEY,
+    uid uuid NOT NULL DEFAULT gen_random_uuid(),
+    name character varying(255) NOT NULL,
+    tags text[] NOT NULL DEFAULT '{}'::text[],
+    scores integer ARRAY,
+    profile jsonb,
+    status public.user_status NOT NULL DEFAULT 'active',
+    active boolean NOT NULL DEFAULT true,
+    balance numeric(10, 2),
+    created_at timestamp(3) with time zone NOT NULL DEFAULT now(),
+    updated_at timestamptz
+);`)
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DDL, 1)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, "user", table.Name)
+		assert.Equal(t, map[string][]string{"user_pkey": {"id"}}, table.Constraint.PrimaryKey)
+
+		expected := map[string]string{
+			"id":         "int64",
+			"uid":        "string",
+			"name":       "string",
+			"tags":       "pq.StringArray",
+			"scores":     "pq.Int64Array",
+			"profile":    "string",
+			"status":     "string",
+			"active":     "bool",
+			"balance":    "decimal.Decimal",
+			"created_at": "time.Time",
+			"updated_at": "time.Time",
+		}
+		assert.Len(t, table.Columns, len(expected))
+		for _, c := range table.Columns {
+			goType, err := c.GoType()
+			assert.Nil(t, err)
+			assert.Equal(t, expected[c.Name], goType, c.Name)
+		}
+
+		id, _ := table.GetColumnByName("id")
+		assert.True(t, id.AutoIncrement)
+		assert.True(t, id.NotNull)
+		created, _ := table.GetColumnByName("created_at")
+		assert.True(t, created.HasDefaultValue)
+		assert.Equal(t, mysql.TypeTimestamp, created.TP)
+		assert.Equal(t, "timestamp with time zone", created.DBType)
+		tags, _ := table.GetColumnByName("tags")
+		assert.Equal(t, "text[]", tags.DBType)
+	})
+
+	t.Run("dump", func(t *testing.T) {
+		dxl, err := ParsePostgres(`
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id integer NOT NULL,
+    code character varying(32) NOT NULL,
+    note text DEFAULT E'it\'s; ok',
+    body text DEFAULT $$a;b$$
+);
+
+COMMENT ON COLUMN public.orders.code IS 'the order''s code';
+CREATE SEQUENCE public.orders_id_seq AS integer START WITH 1;
+ALTER TABLE ONLY public.orders ALTER COLUMN id SET DEFAULT nextval('public.orders_id_seq'::regclass);
+ALTER TABLE ONLY public.orders ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.orders ADD CONSTRAINT orders_code_key UNIQUE (tenant_id, code);
+CREATE INDEX orders_user_id_idx ON public.orders USING btree (user_id);
+CREATE INDEX ON public.orders (lower(code));
+ALTER TABLE public.orders OWNER TO postgres;`)
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DDL, 1)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, spec.Constraint{
+			PrimaryKey: map[string][]string{"orders_pkey": {"id"}},
+			UniqueKey:  map[string][]string{"orders_code_key": {"tenant_id", "code"}},
+			Index:      map[string][]string{"orders_user_id_idx": {"user_id"}},
+			ForeignKey: map[string]spec.ForeignKey{
+				"orders_user_id_fkey": {Columns: []string{"user_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+			},
+		}, table.Constraint)
+
+		id, _ := table.GetColumnByName("id")
+		assert.True(t, id.AutoIncrement)
+		code, _ := table.GetColumnByName("code")
+		assert.Equal(t, "the order's code", code.Comment)
+		note, _ := table.GetColumnByName("note")
+		assert.True(t, note.HasDefaultValue)
+		assert.False(t, note.NotNull)
+	})
+
+	t.Run("index", func(t *testing.T) {
+		dxl, err := ParsePostgres(`CREATE TABLE foo (
+    id serial,
+    email text UNIQUE,
+    name text,
+    CONSTRAINT foo_pk PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX ON foo (name);
+CREATE INDEX ON foo (name, email);`)
+		assert.Nil(t, err)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, map[string][]string{"foo_pk": {"id"}}, table.Constraint.PrimaryKey)
+		assert.Equal(t, map[string][]string{"foo_email_key": {"email"}, "foo_name_key": {"name"}}, table.Constraint.UniqueKey)
+		assert.Equal(t, map[string][]string{"foo_name_email_idx": {"name", "email"}}, table.Constraint.Index)
+	})
+
+	t.Run("foreignKey", func(t *testing.T) {
+		dxl, err := ParsePostgres(`CREATE TABLE users (id bigserial PRIMARY KEY, tenant_id bigint NOT NULL, UNIQUE (tenant_id, id));
+CREATE TABLE orders (
+    id bigserial PRIMARY KEY,
+    user_id bigint REFERENCES users ON DELETE SET NULL NOT NULL,
+    buyer_id bigint CONSTRAINT orders_buyer_fk REFERENCES public.users (id) MATCH FULL ON UPDATE NO ACTION DEFERRABLE INITIALLY DEFERRED,
+    tenant_id bigint NOT NULL,
+    seller_id bigint NOT NULL,
+    CONSTRAINT orders_tenant_fk FOREIGN KEY (tenant_id, user_id) REFERENCES users (tenant_id, id) ON DELETE CASCADE
+);
+ALTER TABLE ONLY public.orders ADD CONSTRAINT orders_seller_id_fkey FOREIGN KEY (seller_id) REFERENCES public.users(id);`)
+		assert.Nil(t, err)
+
+		table := dxl.DDL[1].Table
+		assert.Equal(t, map[string]spec.ForeignKey{
+			"orders_user_id_fkey":   {Columns: []string{"user_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+			"orders_buyer_fk":       {Columns: []string{"buyer_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+			"orders_tenant_fk":      {Columns: []string{"tenant_id", "user_id"}, ReferTable: "users", ReferColumns: []string{"tenant_id", "id"}},
+			"orders_seller_id_fkey": {Columns: []string{"seller_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+		}, table.Constraint.ForeignKey)
+		// the options after the references clause are parsed.
+		userID, _ := table.GetColumnByName("user_id")
+		assert.True(t, userID.NotNull)
+
+		// the foreign keys associate the tables.
+		ctx, err := spec.From(dxl)
+		assert.Nil(t, err)
+		assert.Len(t, ctx[1].BelongsTo, 3)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		dxl, err := ParsePostgres(`CREATE TABLE foo (id bigint PRIMARY KEY, name text);
+-- fn: ListByName
+select * from foo where name = ?;`)
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DML, 1)
+
+		ctx, err := spec.From(dxl)
+		assert.Nil(t, err)
+		assert.Len(t, ctx, 1)
+		assert.Len(t, ctx[0].SelectStmt, 1)
+	})
+
+	t.Run("undefinedTable", func(t *testing.T) {
+		_, err := ParsePostgres(`CREATE INDEX foo_name_idx ON foo (name);`)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("unterminatedString", func(t *testing.T) {
+		_, err := ParsePostgres(`CREATE TABLE foo (id bigint PRIMARY KEY, name text DEFAULT 'foo);`)
+		assert.ErrorContains(t, err, errorUnterminatedString.Error())
+	})
+
+	t.Run("missingPrimaryKey", func(t *testing.T) {
+		_, err := ParsePostgres(`CREATE TABLE foo (id bigint, name text);`)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_splitStatements(t *testing.T) {
+	assert.Equal(t, []string{
+		"select ';'",
+		` select "a;b"`,
+		" -- c;\nselect $x$;$x$",
+		" /* ; */ select 1",
+	}, splitStatements("select ';'; select \"a;b\"; -- c;\nselect $x$;$x$; /* ; */ select 1;\n"))
+}
@@ -392,12 +392,12 @@ func parseFieldList(fieldList *ast.FieldList, from string) (spec.Fields, string,
 			continue
 		}
 
-		columnName, funcSql, tp, aggregate, err := parseSelectField(f.Expr, len(f.AsName.String()) > 0, from)
+		field, funcSql, err := parseSelectField(f.Expr, len(f.AsName.String()) > 0, from)
 		if err != nil {
 			return nil, "", false, err
 		}
 
-		if !aggregate {
+		if !field.AggregateCall {
 			isAllAggregate = false
 		}
 
@@ -405,12 +405,8 @@ func parseFieldList(fieldList *ast.FieldList, from string) (spec.Fields, string,
 			funcSql = fmt.Sprintf("%s AS %s", funcSql, f.AsName.String())
 		}
 		selectField = append(selectField, funcSql)
-		columnSet.Add(spec.Field{
-			ASName:        f.AsName.String(),
-			ColumnName:    columnName,
-			TP:            tp,
-			AggregateCall: aggregate,
-		})
+		field.ASName = f.AsName.String()
+		columnSet.Add(field)
 	}
 
 	var fields spec.Fields
@@ -421,29 +417,29 @@ func parseFieldList(fieldList *ast.FieldList, from string) (spec.Fields, string,
 	return fields, strings.Join(selectField, ", "), isAllAggregate, nil
 }
 
-func parseSelectField(node ast.ExprNode, hasAsName bool, table string) (string, string, byte, bool, error) {
+func parseSelectField(node ast.ExprNode, hasAsName bool, table string) (spec.Field, string, error) {
 	switch v := node.(type) {
 	case *ast.ColumnNameExpr:
 		columnName, err := parseColumn(v.Name, table)
 		if err != nil {
-			return "", "", mysql.TypeUnspecified, false, err
+			return spec.Field{}, "", err
 		}
-		return columnName, columnName, mysql.TypeUnspecified, false, nil
+		return spec.Field{ColumnName: columnName, TP: mysql.TypeUnspecified}, columnName, nil
 	case *ast.AggregateFuncExpr:
-		f, funcSql, t, err := parseAggregateFuncExpr(v, table)
+		f, funcSql, err := parseAggregateFuncExpr(v, table)
 		if err != nil {
-			return "", "", 0, false, err
+			return spec.Field{}, "", err
 		}
 		if !hasAsName {
-			return "", "", 0, false, fmt.Errorf("aggregate function %q must have AS name", funcSql)
+			return spec.Field{}, "", fmt.Errorf("aggregate function %q must have AS name", funcSql)
 		}
-		return f, funcSql, t, true, nil
+		return f, funcSql, nil
 	default:
-		return "", "", mysql.TypeUnspecified, false, fmt.Errorf("unsupported select field: %T", v)
+		return spec.Field{}, "", fmt.Errorf("unsupported select field: %T", v)
 	}
 }
 
-func parseAggregateFuncExpr(node *ast.AggregateFuncExpr, from string) (string, string, byte, error) {
+func parseAggregateFuncExpr(node *ast.AggregateFuncExpr, from string) (spec.Field, string, error) {
 	funcName := node.F
 	args := node.Args
 	getColumnInfo := func() (string, string, error) {
@@ -471,7 +467,7 @@ func parseAggregateFuncExpr(node *ast.AggregateFuncExpr, from string) (string, s
 
 	name, value, err := getColumnInfo()
 	if err != nil {
-		return "", "", mysql.TypeUnspecified, err
+		return spec.Field{}, "", err
 	}
 
 	arg := name
@@ -480,11 +476,17 @@ func parseAggregateFuncExpr(node *ast.AggregateFuncExpr, from string) (string, s
 	}
 	funcSql := fmt.Sprintf("%s(%s)", funcName, arg)
 	tp, ok := funcMap[strings.ToLower(funcName)]
-	if ok {
-		return name, funcSql, tp, nil
+	if !ok {
+		tp = mysql.TypeUnspecified
 	}
 
-	return name, funcSql, mysql.TypeUnspecified, nil
+	return spec.Field{
+		ColumnName:    name,
+		TP:            tp,
+		AggregateCall: true,
+		Func:          funcName,
+		Value:         value,
+	}, funcSql, nil
 }
 
 func parseColumns(cols []*ast.ColumnName, table string) ([]string, error) {
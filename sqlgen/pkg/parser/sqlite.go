@@ -0,0 +1,333 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+// sqliteColumnOptions are the keywords which start a column constraint.
+var sqliteColumnOptions = map[string]struct{}{
+	"constraint": {},
+	"not":        {},
+	"null":       {},
+	"default":    {},
+	"primary":    {},
+	"unique":     {},
+	"check":      {},
+	"references": {},
+	"generated":  {},
+	"as":         {},
+	"collate":    {},
+}
+
+func newSQLiteParser(tokens []token) *ddlParser {
+	return &ddlParser{tokens: tokens, options: sqliteColumnOptions}
+}
+
+// sqliteTypeMapper returns the column type of the declared type, it follows the
+// rules of the type affinity of sqlite, see https://www.sqlite.org/datatype3.html,
+// and the names of the boolean, time, decimal and json types are recognized
+// before the affinity since they are commonly used with the drivers.
+func sqliteTypeMapper(tp string) byte {
+	l := strings.ToLower(tp)
+	switch {
+	case strings.HasPrefix(l, "bool"):
+		return spec.TypeBool
+	case strings.Contains(l, "datetime"), strings.Contains(l, "timestamp"):
+		return mysql.TypeDatetime
+	case l == "date":
+		return mysql.TypeDate
+	case l == "time":
+		return mysql.TypeDuration
+	case strings.Contains(l, "int"):
+		return mysql.TypeLonglong
+	case strings.Contains(l, "char"), strings.Contains(l, "clob"), strings.Contains(l, "text"):
+		return mysql.TypeVarchar
+	case strings.Contains(l, "blob"), len(l) == 0:
+		return mysql.TypeBlob
+	case strings.Contains(l, "real"), strings.Contains(l, "floa"), strings.Contains(l, "doub"):
+		return mysql.TypeDouble
+	case strings.Contains(l, "decimal"), strings.Contains(l, "numeric"):
+		return mysql.TypeNewDecimal
+	case strings.Contains(l, "json"):
+		return mysql.TypeJSON
+	default:
+		return mysql.TypeVarchar
+	}
+}
+
+// sqliteColumnType returns the column type of the type in PRAGMA table_info,
+// e.g. VARCHAR(255), the modifiers are ignored.
+func sqliteColumnType(tp string) (byte, error) {
+	tokens, err := tokenize(tp, true)
+	if err != nil {
+		return 0, err
+	}
+	name, _ := newSQLiteParser(tokens).dataType()
+	return sqliteTypeMapper(name), nil
+}
+
+// isRowID returns true if the column is an alias of the rowid, which is
+// assigned automatically by sqlite, e.g. id INTEGER PRIMARY KEY.
+func isRowID(tp string) bool {
+	return strings.EqualFold(tp, "integer")
+}
+
+// ParseSQLite parses the sqlite DDL and returns a spec.DXL, CREATE TABLE,
+// CREATE INDEX and ALTER TABLE ADD COLUMN are supported, e.g. the output of
+// sqlite3 .schema or .dump, other statements such as PRAGMA, CREATE VIEW and
+// CREATE TRIGGER are ignored, and so are the rows in the transaction of .dump.
+// The queries are parsed by Parse, so they must be written in the syntax
+// which is compatible with mysql, e.g. use ? as the placeholder.
+func ParseSQLite(sql string) (*spec.DXL, error) {
+	var (
+		schema  sqliteSchema
+		queries []string
+		// trigger is true in the body of CREATE TRIGGER, which contains semicolons.
+		trigger bool
+		// data is true in the transaction which has no function name, e.g.
+		// BEGIN TRANSACTION; ... COMMIT; in the output of .dump, the queries
+		// in it are the rows of the tables.
+		data bool
+	)
+	for _, text := range splitStatements(sql) {
+		tokens, err := tokenize(text, true)
+		if err != nil {
+			return nil, errorNearBy(err, strings.TrimSpace(text))
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		p := newSQLiteParser(tokens)
+		switch {
+		case trigger:
+			trigger = !(p.isKeyword("end") && len(tokens) == 1)
+		case p.isKeyword("begin") && !hasFuncName(text):
+			data = true
+		case data && (p.isKeyword("commit") || p.isKeyword("end")):
+			data = false
+		case p.isKeyword("create", "trigger"), p.isKeyword("create", "temp", "trigger"),
+			p.isKeyword("create", "temporary", "trigger"):
+			trigger = p.hasKeyword("begin")
+		case p.isKeyword("create"):
+			err = schema.create(p)
+		case p.isKeyword("alter", "table"):
+			err = schema.alterTable(p)
+		case p.isQuery() && !data:
+			queries = append(queries, text)
+		default:
+			// ignores other statements
+		}
+		if err != nil {
+			return nil, errorNearBy(err, strings.TrimSpace(text))
+		}
+	}
+
+	return schema.dxl(queries)
+}
+
+// hasFuncName returns true if the statement has the function name in the comments.
+func hasFuncName(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		comment, ok := strings.CutPrefix(strings.TrimSpace(line), "--")
+		if ok && strings.HasPrefix(strings.TrimSpace(comment), fnPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type sqliteSchema struct {
+	ddlSchema
+}
+
+func (s *sqliteSchema) create(p *ddlParser) error {
+	p.acceptKeyword("create")
+	unique := p.acceptKeyword("unique")
+	if p.isKeyword("index") {
+		return s.createIndex(p, unique)
+	}
+	p.acceptKeyword("temp")
+	p.acceptKeyword("temporary")
+	if !p.acceptKeyword("table") {
+		// ignores other objects, e.g. CREATE VIEW, CREATE VIRTUAL TABLE.
+		return nil
+	}
+
+	p.acceptKeyword("if", "not", "exists")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if p.isKeyword("as") || strings.HasPrefix(name, "sqlite_") {
+		// CREATE TABLE AS and the internal tables, e.g. sqlite_sequence, are not supported.
+		s.skip(name)
+		return nil
+	}
+	if t, _ := s.table(name); t != nil {
+		return fmt.Errorf("table %q is defined more than once", name)
+	}
+
+	elements, err := p.group()
+	if err != nil {
+		return err
+	}
+	table := &spec.Table{Name: name, Constraint: *spec.NewConstraint()}
+	types := map[string]string{}
+	for _, element := range elements {
+		if err := sqliteTableElement(table, p.sub(element), types); err != nil {
+			return err
+		}
+	}
+
+	// the INTEGER PRIMARY KEY is the alias of the rowid, except in the
+	// tables created with WITHOUT ROWID.
+	if !p.hasKeyword("rowid") {
+		for _, columns := range table.Constraint.PrimaryKey {
+			if len(columns) != 1 || !isRowID(types[columns[0]]) {
+				continue
+			}
+			if column := findColumn(table, columns[0]); column != nil {
+				column.AutoIncrement = true
+				column.NotNull = true
+			}
+		}
+	}
+
+	s.tables = append(s.tables, table)
+	return nil
+}
+
+func sqliteTableElement(table *spec.Table, p *ddlParser, types map[string]string) error {
+	var name string
+	if p.acceptKeyword("constraint") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("primary", "key"):
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		appendPrimaryKey(table, name, columns...)
+	case p.acceptKeyword("unique"):
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		appendUniqueKey(table, name, columns...)
+	case p.acceptKeyword("foreign", "key"):
+		columns, err := p.columns()
+		if err != nil {
+			return err
+		}
+		if !p.acceptKeyword("references") {
+			return fmt.Errorf("missing references of foreign key %q", name)
+		}
+		return references(table, p, name, columns...)
+	case len(name) > 0, p.isKeyword("check"):
+		// ignore other constraints
+	default:
+		column, tp, err := sqliteColumnDef(table, p)
+		if err != nil {
+			return err
+		}
+		types[column.Name] = tp
+		table.Columns = append(table.Columns, *column)
+	}
+	return nil
+}
+
+// sqliteColumnDef returns the column and its declared type, the type is
+// optional in sqlite.
+func sqliteColumnDef(table *spec.Table, p *ddlParser) (*spec.Column, string, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var column spec.Column
+	column.Name = name
+	tp, _ := p.dataType()
+	column.TP = sqliteTypeMapper(tp)
+	column.DBType = strings.ToLower(tp)
+
+	for !p.eof() {
+		var key string
+		if p.acceptKeyword("constraint") {
+			if key, err = p.name(); err != nil {
+				return nil, "", err
+			}
+		}
+
+		switch {
+		case p.acceptKeyword("not", "null"):
+			column.NotNull = true
+		case p.acceptKeyword("null"):
+		case p.acceptKeyword("default"):
+			column.HasDefaultValue = true
+			p.skipExpr()
+		case p.acceptKeyword("primary", "key"):
+			column.NotNull = true
+			appendPrimaryKey(table, key, name)
+			p.acceptKeyword("asc")
+			p.acceptKeyword("desc")
+			if p.acceptKeyword("on", "conflict") {
+				p.next()
+			}
+			if p.acceptKeyword("autoincrement") {
+				column.AutoIncrement = true
+			}
+		case p.acceptKeyword("unique"):
+			appendUniqueKey(table, key, name)
+		case p.acceptKeyword("references"):
+			if err := references(table, p, key, name); err != nil {
+				return nil, "", err
+			}
+		case p.acceptKeyword("generated", "always", "as"), p.acceptKeyword("as"):
+			column.HasDefaultValue = true
+			p.skipExpr()
+		default:
+			// ignore other constraints, e.g. CHECK, COLLATE and ON CONFLICT.
+			p.next()
+			p.skipExpr()
+		}
+	}
+
+	return &column, tp, nil
+}
+
+func (s *sqliteSchema) alterTable(p *ddlParser) error {
+	p.acceptKeyword("alter", "table")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table, err := s.table(name)
+	if err != nil || table == nil {
+		return err
+	}
+
+	if !p.acceptKeyword("add") {
+		// ignores other actions, e.g. RENAME TO, DROP COLUMN.
+		return nil
+	}
+	p.acceptKeyword("column")
+	column, _, err := sqliteColumnDef(table, p)
+	if err != nil {
+		return err
+	}
+	if findColumn(table, column.Name) == nil {
+		table.Columns = append(table.Columns, *column)
+	}
+	return nil
+}
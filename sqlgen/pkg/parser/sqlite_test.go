@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/xyzbit/codegen/sqlgen/pkg/spec"
+)
+
+func TestParseSQLite(t *testing.T) {
+	t.Run("types", func(t *testing.T) {
+		dxl, err := ParseSQLite("CREATE TABLE IF NOT EXISTS \"user\" (\n" +
+			"    id INTEGER PRIMARY KEY,\n" +
+			"    [email] VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,\n" +
+			"    `name` TEXT DEFAULT 'a;b',\n" +
+			"    active BOOLEAN NOT NULL DEFAULT 1,\n" +
+			"    score REAL CHECK (score >= 0),\n" +
+			"    balance DECIMAL(10, 2),\n" +
+			"    age UNSIGNED BIG INT,\n" +
+			"    avatar BLOB,\n" +
+			"    extra,\n" +
+			"    profile JSON,\n" +
+			"    birthday DATE,\n" +
+			"    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+			"    label TEXT GENERATED ALWAYS AS (name || email) VIRTUAL\n" +
+			");")
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DDL, 1)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, "user", table.Name)
+		assert.Equal(t, spec.Constraint{
+			PrimaryKey: map[string][]string{"user_pkey": {"id"}},
+			UniqueKey:  map[string][]string{"user_email_key": {"email"}},
+			Index:      map[string][]string{},
+		}, table.Constraint)
+
+		expected := map[string]string{
+			"id":         "int64",
+			"email":      "string",
+			"name":       "string",
+			"active":     "bool",
+			"score":      "float64",
+			"balance":    "decimal.Decimal",
+			"age":        "int64",
+			"avatar":     "string",
+			"extra":      "string",
+			"profile":    "string",
+			"birthday":   "time.Time",
+			"created_at": "time.Time",
+			"label":      "string",
+		}
+		assert.Len(t, table.Columns, len(expected))
+		for _, c := range table.Columns {
+			goType, err := c.GoType()
+			assert.Nil(t, err)
+			assert.Equal(t, expected[c.Name], goType, c.Name)
+		}
+
+		id, _ := table.GetColumnByName("id")
+		assert.True(t, id.AutoIncrement)
+		assert.True(t, id.NotNull)
+		email, _ := table.GetColumnByName("email")
+		assert.True(t, email.NotNull)
+		created, _ := table.GetColumnByName("created_at")
+		assert.True(t, created.HasDefaultValue)
+		assert.Equal(t, mysql.TypeDatetime, created.TP)
+		label, _ := table.GetColumnByName("label")
+		assert.True(t, label.HasDefaultValue)
+	})
+
+	t.Run("dump", func(t *testing.T) {
+		dxl, err := ParseSQLite(`PRAGMA foreign_keys=OFF;
+BEGIN TRANSACTION;
+CREATE TABLE orders (
+    id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tenant_id INTEGER NOT NULL,
+    code TEXT NOT NULL,
+    PRIMARY KEY (id AUTOINCREMENT),
+    CONSTRAINT orders_code UNIQUE (tenant_id, code) ON CONFLICT REPLACE
+);
+INSERT INTO orders VALUES(1,1,1,'a;b');
+CREATE TABLE sqlite_sequence(name,seq);
+DELETE FROM sqlite_sequence;
+INSERT INTO sqlite_sequence VALUES('orders',1);
+CREATE INDEX orders_user_id ON orders (user_id);
+CREATE INDEX IF NOT EXISTS orders_lower_code ON orders (lower(code));
+CREATE TRIGGER orders_touch AFTER UPDATE ON orders BEGIN
+    UPDATE orders SET code = new.code WHERE id = new.id;
+END;
+CREATE VIEW order_codes AS SELECT code FROM orders;
+ALTER TABLE orders ADD COLUMN note TEXT;
+COMMIT;`)
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DDL, 1)
+		assert.Len(t, dxl.DML, 0)
+
+		table := dxl.DDL[0].Table
+		assert.Equal(t, spec.Constraint{
+			PrimaryKey: map[string][]string{"orders_pkey": {"id"}},
+			UniqueKey:  map[string][]string{"orders_code": {"tenant_id", "code"}},
+			Index:      map[string][]string{"orders_user_id": {"user_id"}},
+			ForeignKey: map[string]spec.ForeignKey{
+				"orders_user_id_fkey": {Columns: []string{"user_id"}, ReferTable: "users", ReferColumns: []string{"id"}},
+			},
+		}, table.Constraint)
+
+		id, _ := table.GetColumnByName("id")
+		assert.True(t, id.AutoIncrement)
+		note, _ := table.GetColumnByName("note")
+		assert.False(t, note.NotNull)
+	})
+
+	t.Run("withoutRowID", func(t *testing.T) {
+		dxl, err := ParseSQLite(`CREATE TABLE kv (id INTEGER PRIMARY KEY, v BLOB) WITHOUT ROWID;`)
+		assert.Nil(t, err)
+
+		id, _ := dxl.DDL[0].Table.GetColumnByName("id")
+		assert.False(t, id.AutoIncrement)
+		assert.True(t, id.NotNull)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		dxl, err := ParseSQLite(`CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT);
+-- fn: ListByName
+select * from foo where name = ?;
+-- fn: Rename
+begin;
+update foo set name = ? where id = ?;
+commit;`)
+		assert.Nil(t, err)
+		assert.Len(t, dxl.DML, 2)
+		assert.IsType(t, &spec.Transaction{}, dxl.DML[1])
+	})
+
+	t.Run("undefinedTable", func(t *testing.T) {
+		_, err := ParseSQLite(`CREATE INDEX foo_name ON foo (name);`)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("unterminatedIdentifier", func(t *testing.T) {
+		_, err := ParseSQLite(`CREATE TABLE foo (id INTEGER PRIMARY KEY, [name TEXT);`)
+		assert.ErrorContains(t, err, errorUnterminatedString.Error())
+	})
+
+	t.Run("missingPrimaryKey", func(t *testing.T) {
+		_, err := ParseSQLite(`CREATE TABLE foo (id INTEGER, name TEXT);`)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_sqliteColumnType(t *testing.T) {
+	for tp, expected := range map[string]byte{
+		"INTEGER":          mysql.TypeLonglong,
+		"VARCHAR(255)":     mysql.TypeVarchar,
+		"NVARCHAR(100)":    mysql.TypeVarchar,
+		"DOUBLE PRECISION": mysql.TypeDouble,
+		"NUMERIC(10,2)":    mysql.TypeNewDecimal,
+		"BOOLEAN":          spec.TypeBool,
+		"TIMESTAMP":        mysql.TypeDatetime,
+		"":                 mysql.TypeBlob,
+		"UUID":             mysql.TypeVarchar,
+	} {
+		actual, err := sqliteColumnType(tp)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual, tp)
+	}
+}
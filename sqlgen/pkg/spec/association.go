@@ -0,0 +1,129 @@
+package spec
+
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/jinzhu/inflection"
+)
+
+// Association is a relation between two tables which is defined by a foreign
+// key, the foreign keys with multiple columns are ignored.
+type Association struct {
+	// Name is the field name of the association, e.g. User of belongs_to,
+	// Orders of has_many.
+	Name string
+	// Table is the associated table.
+	Table *Table
+	// ForeignKey is the column of the foreign key, it is in the table of
+	// belongs_to, or in the associated table of has_many.
+	ForeignKey Column
+	// References is the column referenced by the foreign key.
+	References Column
+}
+
+// JSONName returns the json name of the association field.
+func (a *Association) JSONName() string {
+	return strcase.ToSnake(a.Name)
+}
+
+// associate sets the belongs_to and has_many associations of the contexts by
+// the foreign keys, the foreign keys which reference the tables out of the
+// contexts are ignored.
+func associate(list []Context) {
+	tables := map[string]*Context{}
+	for i := range list {
+		tables[list[i].Table.Name] = &list[i]
+	}
+
+	for i := range list {
+		ctx := &list[i]
+		constraint := ctx.Table.Constraint
+		for _, key := range sortedKeys(constraint.ForeignKey) {
+			foreignKey := constraint.ForeignKey[key]
+			refer, ok := tables[foreignKey.ReferTable]
+			if !ok || len(foreignKey.Columns) != 1 || len(foreignKey.ReferColumns) != 1 {
+				continue
+			}
+			column, ok := ctx.Table.GetColumnByName(foreignKey.Columns[0])
+			if !ok {
+				continue
+			}
+			references, ok := refer.Table.GetColumnByName(foreignKey.ReferColumns[0])
+			if !ok {
+				continue
+			}
+
+			belongsTo := &Association{
+				Name:       belongsToName(column.Name, refer.Table.Name),
+				Table:      refer.Table,
+				ForeignKey: column,
+				References: references,
+			}
+			if !ctx.hasField(belongsTo.Name) {
+				ctx.BelongsTo = append(ctx.BelongsTo, belongsTo)
+			}
+
+			hasMany := &Association{
+				Name:       inflection.Plural(strcase.ToCamel(ctx.Table.Name)),
+				Table:      ctx.Table,
+				ForeignKey: column,
+				References: references,
+			}
+			// the table references another table more than once, e.g.
+			// buyer_id and seller_id, the fields are BuyerOrders and SellerOrders.
+			if countReferences(constraint.ForeignKey, refer.Table.Name) > 1 {
+				hasMany.Name = belongsToName(column.Name, refer.Table.Name) + hasMany.Name
+			}
+			if !refer.hasField(hasMany.Name) {
+				refer.HasMany = append(refer.HasMany, hasMany)
+			}
+		}
+	}
+}
+
+// belongsToName returns the field name of belongs_to, it is the foreign key
+// without the suffix _id, e.g. User of user_id, or the singular name of the
+// referenced table if the foreign key has no such suffix.
+func belongsToName(column, referTable string) string {
+	if name, ok := cutIDSuffix(column); ok {
+		return strcase.ToCamel(name)
+	}
+	return inflection.Singular(strcase.ToCamel(referTable))
+}
+
+func cutIDSuffix(column string) (string, bool) {
+	lower := strings.ToLower(column)
+	if len(column) > 3 && strings.HasSuffix(lower, "_id") {
+		return column[:len(column)-3], true
+	}
+	return "", false
+}
+
+func countReferences(foreignKeys map[string]ForeignKey, table string) int {
+	var count int
+	for _, foreignKey := range foreignKeys {
+		if foreignKey.ReferTable == table && len(foreignKey.Columns) == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// hasField returns true if the field name is used by the columns or the
+// associations of the table.
+func (ctx *Context) hasField(name string) bool {
+	for _, c := range ctx.Table.Columns {
+		if strcase.ToCamel(c.Name) == name {
+			return true
+		}
+	}
+	for _, list := range [][]*Association{ctx.BelongsTo, ctx.HasMany} {
+		for _, a := range list {
+			if a.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -60,6 +60,13 @@ func (b ByItems) SQL() (string, error) {
 	return fmt.Sprintf("`%s`", sql), err
 }
 
+// QuotedSQL returns the by items sql whose columns are quoted by quote, it is
+// not wrapped in backticks, e.g. "id" desc for postgres.
+func (b ByItems) QuotedSQL(quote func(name string) string) (string, error) {
+	sql, _, err := b.marshalQuoted(quote)
+	return sql, err
+}
+
 // ParameterStructure returns the parameter type structure.
 func (b ByItems) ParameterStructure(identifier string) (string, error) {
 	_, parameters, err := b.marshal()
@@ -117,10 +124,14 @@ func (b ByItems) ParameterStructureName(identifier string) string {
 	}
 
 	one := b[0]
-	return strcase.ToCamel(fmt.Sprintf("%s%sParameter", one.Comment.FuncName, identifier))
+	return structureName(one.TableInfo, one.Comment.FuncName, identifier+"Parameter")
 }
 
 func (b ByItems) marshal() (sql string, parameters parameter.Parameters, err error) {
+	return b.marshalQuoted(noQuote)
+}
+
+func (b ByItems) marshalQuoted(quote func(name string) string) (sql string, parameters parameter.Parameters, err error) {
 	parameters = parameter.Empty
 	if len(b) == 0 {
 		return
@@ -130,9 +141,9 @@ func (b ByItems) marshal() (sql string, parameters parameter.Parameters, err err
 	ps := parameter.New()
 	for _, v := range b {
 		if v.Desc {
-			sqlJoin = append(sqlJoin, fmt.Sprintf("%s desc", v.Column))
+			sqlJoin = append(sqlJoin, fmt.Sprintf("%s desc", quote(v.Column)))
 		} else {
-			sqlJoin = append(sqlJoin, v.Column)
+			sqlJoin = append(sqlJoin, quote(v.Column))
 		}
 
 		p, err := v.ColumnInfo.DataType()
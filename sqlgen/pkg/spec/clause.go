@@ -57,6 +57,17 @@ func (c *Clause) SQL() (string, error) {
 	return fmt.Sprintf("`%s`", sql), err
 }
 
+// QuotedSQL returns the clause sql whose columns are quoted by quote, it is
+// not wrapped in backticks, e.g. "id" = ? for postgres.
+func (c *Clause) QuotedSQL(quote func(name string) string) (string, error) {
+	if !c.IsValid() {
+		return "", nil
+	}
+
+	sql, _, err := c.marshalQuoted(quote)
+	return sql, err
+}
+
 // ParameterStructure returns the parameter type structure.
 func (c *Clause) ParameterStructure(identifier string) (string, error) {
 	if !c.IsValid() {
@@ -85,7 +96,7 @@ func (c *Clause) ParameterStructureName(identifier string) string {
 	if !c.IsValid() {
 		return ""
 	}
-	return strcase.ToCamel(fmt.Sprintf("%s%sParameter", c.Comment.FuncName, identifier))
+	return structureName(c.TableInfo, c.Comment.FuncName, identifier+"Parameter")
 }
 
 // ParameterThirdImports returns the third package imports.
@@ -111,23 +122,71 @@ func (c *Clause) ParameterThirdImports() (string, error) {
 
 // Parameters returns the parameter variables.
 func (c *Clause) Parameters(pkg string) (string, error) {
+	list, err := c.ParameterList(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(list, ", "), nil
+}
+
+// ParameterList returns the parameter variables in order, the variables of the
+// columns with converted type overrides are wrapped by the conversion functions.
+func (c *Clause) ParameterList(pkg string) ([]string, error) {
 	if !c.IsValid() {
-		return "", nil
+		return nil, nil
 	}
 
 	_, parameters, err := c.marshal()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	var list []string
 	for _, v := range parameters {
-		list = append(list, fmt.Sprintf("%s.%s", pkg, v.Column))
+		list = append(list, v.Arg(pkg))
 	}
 
-	return strings.Join(list, ", "), nil
+	return list, nil
+}
+
+// InParameters returns the parameter variables of the IN and NOT IN expressions,
+// some orm require wrapping them before passing to the query, e.g. bun.In.
+func (c *Clause) InParameters(pkg string) ([]string, error) {
+	if !c.IsValid() {
+		return nil, nil
+	}
+
+	var list []string
+	switch c.OP {
+	case And, Or, Parentheses:
+		left, err := c.Left.InParameters(pkg)
+		if err != nil {
+			return nil, err
+		}
+		right, err := c.Right.InParameters(pkg)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, left...)
+		list = append(list, right...)
+	case In, NotIn:
+		p, err := c.ColumnInfo.DataType()
+		if err != nil {
+			return nil, err
+		}
+		p.Column += OpName[c.OP]
+		p.Converter = c.ColumnInfo.converter(true)
+		list = append(list, p.Arg(pkg))
+	}
+
+	return list, nil
 }
 
 func (c *Clause) marshal() (sql string, parameters parameter.Parameters, err error) {
+	return c.marshalQuoted(noQuote)
+}
+
+func (c *Clause) marshalQuoted(quote func(name string) string) (sql string, parameters parameter.Parameters, err error) {
 	if !c.IsValid() {
 		return
 	}
@@ -136,12 +195,12 @@ func (c *Clause) marshal() (sql string, parameters parameter.Parameters, err err
 	ps := parameter.New()
 	switch c.OP {
 	case And, Or:
-		leftSQL, leftParameter, err := c.Left.marshal()
+		leftSQL, leftParameter, err := c.Left.marshalQuoted(quote)
 		if err != nil {
 			return "", nil, err
 		}
 
-		rightSQL, rightParameter, err := c.Right.marshal()
+		rightSQL, rightParameter, err := c.Right.marshalQuoted(quote)
 		if err != nil {
 			return "", nil, err
 		}
@@ -158,48 +217,54 @@ func (c *Clause) marshal() (sql string, parameters parameter.Parameters, err err
 
 		sql = strings.Join(sqlList, " "+Operator[c.OP]+" ")
 	case EQ, GE, GT, LE, LT, Like, NE, Not, NotLike:
-		sql = fmt.Sprintf("%s %s ?", c.Column, Operator[c.OP])
+		sql = fmt.Sprintf("%s %s ?", quote(c.Column), Operator[c.OP])
 		p, err := c.ColumnInfo.DataType()
 		if err != nil {
 			return "", nil, err
 		}
 
 		ps.Add(parameter.Parameter{
-			Column:   p.Column + OpName[c.OP],
-			Type:     p.Type,
-			ThirdPkg: p.ThirdPkg,
+			Column:    p.Column + OpName[c.OP],
+			Type:      p.Type,
+			ThirdPkg:  p.ThirdPkg,
+			Converter: p.Converter,
 		})
 	case In, NotIn:
-		sql = fmt.Sprintf("%s %s (?)", c.Column, Operator[c.OP])
-		p, err := c.ColumnInfo.DataType()
+		sql = fmt.Sprintf("%s %s (?)", quote(c.Column), Operator[c.OP])
+		// NULL never matches the elements of the list, so the elements use the not null type.
+		column := c.ColumnInfo
+		column.NullStrategy = NullNone
+		p, err := column.DataType()
 		if err != nil {
 			return "", nil, err
 		}
 
 		p.Type = fmt.Sprintf("[]%s", p.Type)
 		ps.Add(parameter.Parameter{
-			Column:   p.Column + OpName[c.OP],
-			Type:     p.Type,
-			ThirdPkg: p.ThirdPkg,
+			Column:    p.Column + OpName[c.OP],
+			Type:      p.Type,
+			ThirdPkg:  p.ThirdPkg,
+			Converter: column.converter(true),
 		})
 	case Between, NotBetween:
-		sql = fmt.Sprintf("%s %s ? AND ?", c.Column, Operator[c.OP])
+		sql = fmt.Sprintf("%s %s ? AND ?", quote(c.Column), Operator[c.OP])
 		p, err := c.ColumnInfo.DataType()
 		if err != nil {
 			return "", nil, err
 		}
 
-		ps.Add(
-			NewParameter(fmt.Sprintf("%s%sStart", c.Column, OpName[c.OP]), p.Type, p.ThirdPkg),
-			NewParameter(fmt.Sprintf("%s%sEnd", c.Column, OpName[c.OP]), p.Type, p.ThirdPkg))
+		start := NewParameter(fmt.Sprintf("%s%sStart", c.Column, OpName[c.OP]), p.Type, p.ThirdPkg)
+		end := NewParameter(fmt.Sprintf("%s%sEnd", c.Column, OpName[c.OP]), p.Type, p.ThirdPkg)
+		start.Converter, end.Converter = p.Converter, p.Converter
+		ps.Add(start, end)
 	case Parentheses:
-		leftSQL, leftParameter, err := c.Left.marshal()
+		leftSQL, leftParameter, err := c.Left.marshalQuoted(quote)
 		if err != nil {
 			return "", nil, err
 		}
 
 		// assert right clause is nil
-		//rightSQL, rightParameter, err := c.Right.marshal()
+		//rightSQL, rightParameter, err := c.Right.marshalQuoted(quote)
 		//if err != nil {
 		//	return "", nil, err
 		//}
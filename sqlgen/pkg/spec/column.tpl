@@ -1 +1 @@
-{{UpperCamel .Name}} {{.GoType}} `{{ColumnTag}}json:"{{LowerCamel .Name}}"`
\ No newline at end of file
+{{UpperCamel .Name}} {{.POGoType}} `{{ColumnTag}}json:"{{LowerCamel .Name}}"`
\ No newline at end of file
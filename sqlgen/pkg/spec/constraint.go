@@ -1,6 +1,8 @@
 package spec
 
 import (
+	"sort"
+
 	"github.com/xyzbit/codegen/pkg/set"
 )
 
@@ -43,9 +45,17 @@ func (c *Constraint) AppendIndex(key string, columns ...string) {
 	}, key, columns...)
 }
 
+// AppendForeignKey appends a foreign key, the foreign key with the same name is replaced.
+func (c *Constraint) AppendForeignKey(key string, foreignKey ForeignKey) {
+	if c.ForeignKey == nil {
+		c.ForeignKey = map[string]ForeignKey{}
+	}
+	c.ForeignKey[key] = foreignKey
+}
+
 // IsEmpty returns true if the constraint is empty.
 func (c *Constraint) IsEmpty() bool {
-	return len(c.PrimaryKey) == 0 && len(c.UniqueKey) == 0 && len(c.Index) == 0
+	return len(c.PrimaryKey) == 0 && len(c.UniqueKey) == 0 && len(c.Index) == 0 && len(c.ForeignKey) == 0
 }
 
 // Merge merges the constraint with another constraint.
@@ -57,16 +67,20 @@ func (c *Constraint) Merge(constraint *Constraint) {
 		return
 	}
 
-	for key, columns := range constraint.PrimaryKey {
-		c.AppendPrimaryKey(key, columns...)
+	for _, key := range sortedKeys(constraint.PrimaryKey) {
+		c.AppendPrimaryKey(key, constraint.PrimaryKey[key]...)
 	}
 
-	for key, columns := range constraint.UniqueKey {
-		c.AppendUniqueKey(key, columns...)
+	for _, key := range sortedKeys(constraint.UniqueKey) {
+		c.AppendUniqueKey(key, constraint.UniqueKey[key]...)
 	}
 
-	for key, columns := range constraint.Index {
-		c.AppendIndex(key, columns...)
+	for _, key := range sortedKeys(constraint.Index) {
+		c.AppendIndex(key, constraint.Index[key]...)
+	}
+
+	for _, key := range sortedKeys(constraint.ForeignKey) {
+		c.AppendForeignKey(key, constraint.ForeignKey[key])
 	}
 }
 
@@ -89,3 +103,37 @@ func (c *Constraint) append(existFn func(key string) ([]string, bool), result fu
 
 	result(columnSet.String())
 }
+
+// PrimaryKeyList returns the columns of the primary keys in the order of the key names.
+func (c *Constraint) PrimaryKeyList() [][]string {
+	return sortedValues(c.PrimaryKey)
+}
+
+// UniqueKeyList returns the columns of the unique keys in the order of the key names.
+func (c *Constraint) UniqueKeyList() [][]string {
+	return sortedValues(c.UniqueKey)
+}
+
+// IndexList returns the columns of the indexes in the order of the key names.
+func (c *Constraint) IndexList() [][]string {
+	return sortedValues(c.Index)
+}
+
+func sortedValues[V any](m map[string]V) []V {
+	var list []V
+	for _, key := range sortedKeys(m) {
+		list = append(list, m[key])
+	}
+	return list
+}
+
+// sortedKeys returns the keys of the constraint map in sorted order, the iteration
+// order of map is random and the generated code must be deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
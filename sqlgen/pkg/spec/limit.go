@@ -95,7 +95,7 @@ func (l *Limit) ParameterStructureName() string {
 		return ""
 	}
 
-	return strcase.ToCamel(fmt.Sprintf("%sLimitParameter", l.Comment.FuncName))
+	return structureName(l.TableInfo, l.Comment.FuncName, "LimitParameter")
 }
 
 func (l *Limit) One() bool {
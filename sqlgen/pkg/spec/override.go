@@ -0,0 +1,153 @@
+package spec
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeOverride overrides the Go type of the columns matched by the declared type,
+// the table.column glob or the column name regex, all the configured conditions
+// must match.
+type TypeOverride struct {
+	// DBType matches the declared type of the column case-insensitively, e.g. tinyint(1),
+	// the type without length matches any length, e.g. json, varchar.
+	DBType string
+	// Column matches the table.column of the column by glob, e.g. *.status, orders.amount.
+	Column string
+	// ColumnRegex matches the column name.
+	ColumnRegex *regexp.Regexp
+	// GoType is the Go type of the column, e.g. uuid.UUID.
+	GoType string
+	// Import is the import path of the Go type, e.g. github.com/google/uuid.
+	Import string
+	// ToDB converts the Go type to the default Go type of the column, e.g. money.ToCents.
+	ToDB string
+	// FromDB converts the default Go type of the column to the Go type, e.g. money.FromCents.
+	FromDB string
+}
+
+var lengthExpr = regexp.MustCompile(`\([^)]*\)`)
+
+// Match returns true if the column of the table matches all the configured conditions.
+func (o *TypeOverride) Match(table string, c Column) bool {
+	if o.DBType == "" && o.Column == "" && o.ColumnRegex == nil {
+		return false
+	}
+	if o.DBType != "" && !matchDBType(strings.ToLower(strings.TrimSpace(o.DBType)), c.DBType) {
+		return false
+	}
+	if o.Column != "" {
+		if ok, _ := path.Match(o.Column, table+"."+c.Name); !ok {
+			return false
+		}
+	}
+	if o.ColumnRegex != nil && !o.ColumnRegex.MatchString(c.Name) {
+		return false
+	}
+	return true
+}
+
+// matchDBType matches the declared type as is, without length, e.g. bigint unsigned,
+// or only the type name, e.g. bigint.
+func matchDBType(pattern, dbType string) bool {
+	if dbType == "" {
+		return false
+	}
+	withoutLength := strings.Join(strings.Fields(lengthExpr.ReplaceAllString(dbType, "")), " ")
+	name := strings.Fields(withoutLength)
+	return pattern == dbType || pattern == withoutLength || (len(name) > 0 && pattern == name[0])
+}
+
+// Converted returns true if the Go type is converted from and to the default Go type of the
+// column, the persistent object keeps the default Go type.
+func (o *TypeOverride) Converted() bool {
+	return o != nil && o.ToDB != ""
+}
+
+// Validate checks the override.
+func (o *TypeOverride) Validate() error {
+	if o.GoType == "" {
+		return fmt.Errorf("missing go_type of type override")
+	}
+	if o.DBType == "" && o.Column == "" && o.ColumnRegex == nil {
+		return fmt.Errorf("type override %q requires at least one of db_type, column and column_regex", o.GoType)
+	}
+	if o.Column != "" {
+		if _, err := path.Match(o.Column, ""); err != nil {
+			return fmt.Errorf("invalid column glob %q of type override %q: %w", o.Column, o.GoType, err)
+		}
+	}
+	if (o.ToDB == "") != (o.FromDB == "") {
+		return fmt.Errorf("type override %q requires both to_db and from_db", o.GoType)
+	}
+	return nil
+}
+
+// SetTypeOverrides sets the first matched override of each column. The converted
+// override is not supported by the primary key, the foreign key and the nullable
+// column of which the Go type represents NULL.
+func (t *Table) SetTypeOverrides(list []*TypeOverride) error {
+	for i, c := range t.Columns {
+		for _, o := range list {
+			if !o.Match(t.Name, c) {
+				continue
+			}
+			if o.Converted() {
+				switch {
+				case t.IsPrimary(c.Name):
+					return fmt.Errorf("type override %q with conversion functions does not support the primary key %s.%s", o.GoType, t.Name, c.Name)
+				case t.isForeignKey(c.Name):
+					return fmt.Errorf("type override %q with conversion functions does not support the foreign key %s.%s", o.GoType, t.Name, c.Name)
+				case c.Nullable():
+					return fmt.Errorf("type override %q with conversion functions does not support the nullable column %s.%s", o.GoType, t.Name, c.Name)
+				}
+			}
+			t.Columns[i].TypeOverride = o
+			break
+		}
+	}
+	return nil
+}
+
+func (t *Table) isForeignKey(name string) bool {
+	for _, fk := range t.Constraint.ForeignKey {
+		for _, c := range fk.Columns {
+			if c == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasConvertedColumn returns true if the Go type of any column is converted, the
+// persistent object and the entity have different Go types.
+func (t *Table) HasConvertedColumn() bool {
+	for _, c := range t.Columns {
+		if c.TypeOverride.Converted() {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeImports returns the sorted import paths of the overridden Go types of the table.
+func (t *Table) TypeImports() []string {
+	seen := map[string]struct{}{}
+	var list []string
+	for _, c := range t.Columns {
+		if c.TypeOverride == nil || c.TypeOverride.Import == "" {
+			continue
+		}
+		if _, ok := seen[c.TypeOverride.Import]; ok {
+			continue
+		}
+		seen[c.TypeOverride.Import] = struct{}{}
+		list = append(list, c.TypeOverride.Import)
+	}
+	sort.Strings(list)
+	return list
+}
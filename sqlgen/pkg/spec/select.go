@@ -4,7 +4,6 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"strings"
 	"text/template"
 
 	"github.com/iancoleman/strcase"
@@ -65,7 +64,7 @@ func (s *SelectStmt) TableName() string {
 
 func (s *SelectStmt) ReceiverName() string {
 	if s.ContainsExtraColumns() {
-		return strcase.ToCamel(fmt.Sprintf("%sResult", s.FuncName))
+		return structureName(s.FromInfo, s.FuncName, "Result")
 	}
 	return strcase.ToCamel(s.TableName())
 }
@@ -75,7 +74,7 @@ var fieldTpl string
 
 func (s *SelectStmt) ReceiverStructure(orm string) string {
 	receiverName := s.ReceiverName()
-	if strings.EqualFold(receiverName, s.TableName()) {
+	if !s.ContainsExtraColumns() {
 		// Use table struct
 		return ""
 	}
@@ -96,7 +95,8 @@ func (s *SelectStmt) ReceiverStructure(orm string) string {
 				case ormGorm:
 					return fmt.Sprintf(`gorm:"column:%s" `, v.Name)
 				case ormSQL:
-					return "" // placeholder
+					// database/sql scans the columns in order explicitly, no tag is required.
+					return ""
 				case ormSQLX:
 					return fmt.Sprintf(`db:"%s" `, v.Name)
 				case ormXorm:
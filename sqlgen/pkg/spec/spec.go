@@ -23,3 +23,29 @@ func (dxl *DXL) Validate() error {
 	}
 	return nil
 }
+
+// AppendDML appends the dml statements whose function names are not defined
+// in the same table, the statements written by user take precedence.
+func (dxl *DXL) AppendDML(list ...DML) {
+	defined := map[string]struct{}{}
+	for _, dml := range dxl.DML {
+		funcM, _ := dml.validate()
+		for fn := range funcM {
+			defined[dml.TableName()+"."+fn] = struct{}{}
+		}
+	}
+
+	for _, dml := range list {
+		funcM, _ := dml.validate()
+		var exists bool
+		for fn := range funcM {
+			if _, ok := defined[dml.TableName()+"."+fn]; ok {
+				exists = true
+			}
+		}
+		if exists {
+			continue
+		}
+		dxl.DML = append(dxl.DML, dml)
+	}
+}
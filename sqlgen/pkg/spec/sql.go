@@ -10,6 +10,10 @@ type Context struct {
 	UpdateStmt  []*UpdateStmt
 	DeleteStmt  []*DeleteStmt
 	Transaction []*Transaction
+	// BelongsTo are the associations to the tables referenced by the foreign keys of the table.
+	BelongsTo []*Association
+	// HasMany are the associations to the tables whose foreign keys reference the table.
+	HasMany []*Association
 }
 
 // From creates context from table and dml.
@@ -24,9 +28,38 @@ func From(dxl *DXL) ([]Context, error) {
 		list = append(list, ctx)
 	}
 
+	associate(list)
+	collide(list)
 	return list, nil
 }
 
+// collide records the function names which are defined in multiple tables,
+// the structure names of these functions are prefixed with the table name.
+func collide(list []Context) {
+	var (
+		funcNames = make([]map[string]string, len(list))
+		tables    = map[string]int{}
+	)
+	for i, ctx := range list {
+		funcNames[i], _ = ctx.validate()
+		for fn := range funcNames[i] {
+			tables[fn]++
+		}
+	}
+
+	for i, ctx := range list {
+		for fn := range funcNames[i] {
+			if tables[fn] < 2 {
+				continue
+			}
+			if ctx.Table.collisions == nil {
+				ctx.Table.collisions = map[string]struct{}{}
+			}
+			ctx.Table.collisions[fn] = struct{}{}
+		}
+	}
+}
+
 func from(table *Table, dml []DML) (Context, error) {
 	var ctx Context
 	ctx.Table = table
@@ -105,7 +138,7 @@ func from(table *Table, dml []DML) (Context, error) {
 					ctx.DeleteStmt = append(ctx.DeleteStmt, v)
 				}
 			}
-		} else {
+		} else if transaction.TableName() == table.Name {
 			childCtx, err := from(table, transaction.Statements)
 			if err != nil {
 				return Context{}, err
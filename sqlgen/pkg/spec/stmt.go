@@ -2,6 +2,9 @@ package spec
 
 import (
 	_ "embed"
+	"fmt"
+
+	"github.com/iancoleman/strcase"
 )
 
 // WildCard is a wildcard column.
@@ -20,6 +23,11 @@ type Field struct {
 	ColumnName    string
 	TP            byte
 	AggregateCall bool
+	// Func is the name of the aggregate function, e.g. count.
+	Func string
+	// Value is the literal argument of the aggregate function, e.g. 1 of
+	// count(1), it is empty if the argument is a column.
+	Value string
 }
 
 // Limit represents a limit clause.
@@ -44,3 +52,36 @@ func (f Field) Name() string {
 	}
 	return f.ColumnName
 }
+
+// QuotedSQL returns the select field sql whose identifiers are quoted by quote,
+// e.g. count("id") AS "c" for postgres.
+func (f Field) QuotedSQL(quote func(name string) string) string {
+	sql := quote(f.ColumnName)
+	if f.AggregateCall {
+		arg := f.Value
+		if len(f.ColumnName) > 0 {
+			arg = quote(f.ColumnName)
+		}
+		sql = fmt.Sprintf("%s(%s)", f.Func, arg)
+	}
+	if len(f.ASName) > 0 {
+		sql = fmt.Sprintf("%s AS %s", sql, quote(f.ASName))
+	}
+	return sql
+}
+
+// noQuote returns the name as it is.
+func noQuote(name string) string {
+	return name
+}
+
+// structureName returns the structure name of the function, e.g.
+// FindOneWhereParameter, it is prefixed with the table name only if the
+// function name is defined in multiple tables, so that the structures of
+// different tables can live in the same package, e.g. UserFindOneWhereParameter.
+func structureName(table *Table, funcName, suffix string) string {
+	if table != nil && table.collides(funcName) {
+		return strcase.ToCamel(fmt.Sprintf("%s_%s_%s", table.Name, funcName, suffix))
+	}
+	return strcase.ToCamel(fmt.Sprintf("%s%s", funcName, suffix))
+}
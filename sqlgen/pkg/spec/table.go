@@ -3,7 +3,10 @@ package spec
 import (
 	"fmt"
 
+	"github.com/iancoleman/strcase"
 	"github.com/zeromicro/go-zero/core/stringx"
+
+	"github.com/xyzbit/codegen/pkg/buffer"
 )
 
 // Table represents a table in the database.
@@ -11,12 +14,15 @@ type Table struct {
 	// Columns is the list of columns in the table.
 	Columns Columns
 	// Constraint is a struct that contains the constraints of a table.
-	// ConstraintForeignKey,ConstraintFulltext,ConstraintCheck are ignored.
+	// ConstraintFulltext,ConstraintCheck are ignored.
 	Constraint Constraint
 	// Schema is the name of the schema that the table belongs to.
 	Schema string
 	// Name is the name of the table.
 	Name string
+
+	// collisions are the function names which are also defined in other tables.
+	collisions map[string]struct{}
 }
 
 type Columns []Column
@@ -30,6 +36,12 @@ type Column struct {
 	// TP is the type of the column.
 	TP            byte
 	AggregateCall bool
+	// NullStrategy is the strategy of the Go type if the column is nullable.
+	NullStrategy NullStrategy
+	// DBType is the declared type of the column in lower case, e.g. tinyint(1), varchar(255), json.
+	DBType string
+	// TypeOverride overrides the Go type of the column, it is nil if no override matches the column.
+	TypeOverride *TypeOverride
 }
 
 // ColumnOption is a column option.
@@ -48,7 +60,7 @@ type ColumnOption struct {
 }
 
 // Constraint is a struct that contains the constraints of a table.
-// ConstraintForeignKey,ConstraintFulltext,ConstraintCheck are ignored.
+// ConstraintFulltext,ConstraintCheck are ignored.
 type Constraint struct {
 	// Index is a list of column names that are part of an index, the key of map
 	//	// is the key name, the values are the column list.
@@ -59,6 +71,19 @@ type Constraint struct {
 	// UniqueKey is a list of column names that are part of a unique ke, the key of map
 	//	// is the key name, the values are the column list.
 	UniqueKey map[string][]string
+	// ForeignKey is a list of foreign keys, the key of map is the key name.
+	ForeignKey map[string]ForeignKey
+}
+
+// ForeignKey is a foreign key which references the columns of another table.
+type ForeignKey struct {
+	// Columns is the list of column names in the table.
+	Columns []string
+	// ReferTable is the name of the referenced table.
+	ReferTable string
+	// ReferColumns is the list of referenced column names, they are in the
+	// same order as Columns.
+	ReferColumns []string
 }
 
 // Has returns true if Columns has specified column.
@@ -79,6 +104,12 @@ func (cs Columns) GetColumn(name string) (Column, bool) {
 	return Column{}, false
 }
 
+// collides returns true if the function name is also defined in other tables.
+func (t *Table) collides(funcName string) bool {
+	_, ok := t.collisions[funcName]
+	return ok
+}
+
 // IsPrimary returns true if the column is part of the primary key.
 func (t *Table) IsPrimary(name string) bool {
 	for _, c := range t.Constraint.PrimaryKey {
@@ -89,6 +120,17 @@ func (t *Table) IsPrimary(name string) bool {
 	return false
 }
 
+// SetNullStrategy sets the strategy of the Go type of the nullable columns,
+// the primary key columns are always treated as not null.
+func (t *Table) SetNullStrategy(strategy NullStrategy) {
+	for i, c := range t.Columns {
+		if t.IsPrimary(c.Name) {
+			continue
+		}
+		t.Columns[i].NullStrategy = strategy
+	}
+}
+
 // ColumnList is a list of column names.
 func (t *Table) ColumnList() []string {
 	var list []string
@@ -101,8 +143,8 @@ func (t *Table) ColumnList() []string {
 // PrimaryColumnList is a list of column names that are part of the primary key.
 func (t *Table) PrimaryColumnList() Columns {
 	var ret Columns
-	for _, list := range t.Constraint.PrimaryKey {
-		for _, name := range list {
+	for _, columns := range t.Constraint.PrimaryKeyList() {
+		for _, name := range columns {
 			c, ok := t.GetColumnByName(name)
 			if !ok {
 				continue
@@ -127,6 +169,34 @@ func (t *Table) HasOnePrimaryKey() bool {
 	return len(t.PrimaryColumnList()) == 1
 }
 
+// HasCompositePrimaryKey returns true if the primary key consists of multiple columns.
+func (t *Table) HasCompositePrimaryKey() bool {
+	return len(t.PrimaryColumnList()) > 1
+}
+
+// KeyStructureName returns the name of the composite primary key structure.
+func (t *Table) KeyStructureName() string {
+	return strcase.ToCamel(t.Name + "_key")
+}
+
+// KeyStructure returns the composite primary key structure, the fields are
+// in the order of the primary key columns.
+func (t *Table) KeyStructure() (string, error) {
+	writer := buffer.New()
+	writer.Write(`// %s is the primary key of %s.`, t.KeyStructureName(), t.Name)
+	writer.Write(`type %s struct {`, t.KeyStructureName())
+	for _, c := range t.PrimaryColumnList() {
+		p, err := c.DataType()
+		if err != nil {
+			return "", err
+		}
+		writer.Write("%s %s", p.Column, p.Type)
+	}
+	writer.Write(`}`)
+
+	return writer.String(), nil
+}
+
 // GetColumnByName returns the column with the given name.
 func (t *Table) GetColumnByName(name string) (Column, bool) {
 	for _, c := range t.Columns {
@@ -148,7 +218,7 @@ func (t *Table) validate() error {
 		return fmt.Errorf("missing table primary key")
 	}
 	if len(t.Constraint.PrimaryKey) > 1 {
-		return fmt.Errorf("unsupported multiple primary key")
+		return fmt.Errorf("multiple primary key defined in table %q, use PRIMARY KEY (a, b) to define a composite primary key", t.Name)
 	}
 	return nil
 }
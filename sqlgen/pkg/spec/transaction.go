@@ -1,5 +1,13 @@
 package spec
 
+import (
+	"fmt"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/xyzbit/codegen/pkg/buffer"
+)
+
 type Transaction struct {
 	// Action represents the db action.
 	Action Action
@@ -18,12 +26,36 @@ func (t Transaction) SQLText() string {
 	return t.SQL
 }
 
+// TableName returns the table of the statements, it returns empty string
+// if the statements operate multiple tables.
 func (t Transaction) TableName() string {
-	return ""
+	var table string
+	for _, v := range t.Statements {
+		if len(table) > 0 && table != v.TableName() {
+			return ""
+		}
+		table = v.TableName()
+	}
+	return table
 }
 
 func (t Transaction) validate() (map[string]string, error) {
-	return t.Context.validate()
+	if err := t.Comment.validate(); err != nil {
+		return nil, err
+	}
+	if len(t.TableName()) == 0 {
+		return nil, fmt.Errorf("unsupported multiple tables in transaction %q", t.FuncName)
+	}
+
+	funcName, err := t.Context.validate()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := funcName[t.FuncName]; ok {
+		return nil, fmt.Errorf("duplicate function %q near by %q", t.FuncName, t.OriginText)
+	}
+	funcName[t.FuncName] = t.OriginText
+	return funcName, nil
 }
 
 func (t Transaction) HasArg() bool {
@@ -49,3 +81,123 @@ func (t Transaction) HasArg() bool {
 	}
 	return false
 }
+
+// HasParameter returns true if the transaction needs a parameter structure,
+// the data of insert and update statements are also treated as parameters.
+func (t Transaction) HasParameter() bool {
+	if len(t.InsertStmt) > 0 || len(t.UpdateStmt) > 0 {
+		return true
+	}
+	for _, v := range t.SelectStmt {
+		if v.Where.IsValid() || v.Having.IsValid() || v.Limit.Multiple() {
+			return true
+		}
+	}
+	for _, v := range t.DeleteStmt {
+		if v.Where.IsValid() || v.Limit.Multiple() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasResult returns true if the transaction contains select statements.
+func (t Transaction) HasResult() bool {
+	return len(t.SelectStmt) > 0
+}
+
+// ParameterStructureName returns the parameter structure name.
+func (t Transaction) ParameterStructureName() string {
+	if !t.HasParameter() {
+		return ""
+	}
+	return structureName(t.Table, t.FuncName, "Parameter")
+}
+
+// ParameterStructure returns the parameter type structure which merges the
+// parameters of all statements in the transaction, entityPkg is the package
+// name of the table entity.
+func (t Transaction) ParameterStructure(entityPkg string) string {
+	if !t.HasParameter() {
+		return ""
+	}
+
+	entity := fmt.Sprintf("%s.%s", entityPkg, strcase.ToCamel(t.Table.Name))
+	writer := buffer.New()
+	writer.Write(`// %s is a %s transaction parameter structure.`, t.ParameterStructureName(), strcase.ToDelimited(t.FuncName, ' '))
+	writer.Write(`type %s struct {`, t.ParameterStructureName())
+	for _, v := range t.Statements {
+		switch stmt := v.(type) {
+		case *InsertStmt:
+			writer.Write("%sData []*%s", strcase.ToCamel(stmt.FuncName), entity)
+		case *SelectStmt:
+			if stmt.Where.IsValid() {
+				writer.Write("%sWhere %s", strcase.ToCamel(stmt.FuncName), stmt.Where.ParameterStructureName("Where"))
+			}
+			if stmt.Having.IsValid() {
+				writer.Write("%sHaving %s", strcase.ToCamel(stmt.FuncName), stmt.Having.ParameterStructureName("Having"))
+			}
+			if stmt.Limit.Multiple() {
+				writer.Write("%sLimit %s", strcase.ToCamel(stmt.FuncName), stmt.Limit.ParameterStructureName())
+			}
+		case *UpdateStmt:
+			writer.Write("%sData *%s", strcase.ToCamel(stmt.FuncName), entity)
+			if stmt.Where.IsValid() {
+				writer.Write("%sWhere %s", strcase.ToCamel(stmt.FuncName), stmt.Where.ParameterStructureName("Where"))
+			}
+			if stmt.Limit.Multiple() {
+				writer.Write("%sLimit %s", strcase.ToCamel(stmt.FuncName), stmt.Limit.ParameterStructureName())
+			}
+		case *DeleteStmt:
+			if stmt.Where.IsValid() {
+				writer.Write("%sWhere %s", strcase.ToCamel(stmt.FuncName), stmt.Where.ParameterStructureName("Where"))
+			}
+			if stmt.Limit.Multiple() {
+				writer.Write("%sLimit %s", strcase.ToCamel(stmt.FuncName), stmt.Limit.ParameterStructureName())
+			}
+		}
+	}
+	writer.Write(`}`)
+
+	return writer.String()
+}
+
+// ResultStructureName returns the result structure name.
+func (t Transaction) ResultStructureName() string {
+	if !t.HasResult() {
+		return ""
+	}
+	return structureName(t.Table, t.FuncName, "Result")
+}
+
+// ResultStructure returns the result type structure which contains the result
+// of all select statements in the transaction, entityPkg is the package name
+// of the table entity.
+func (t Transaction) ResultStructure(entityPkg string) string {
+	if !t.HasResult() {
+		return ""
+	}
+
+	writer := buffer.New()
+	writer.Write(`// %s is a %s transaction result structure.`, t.ResultStructureName(), strcase.ToDelimited(t.FuncName, ' '))
+	writer.Write(`type %s struct {`, t.ResultStructureName())
+	for _, v := range t.Statements {
+		stmt, ok := v.(*SelectStmt)
+		if !ok {
+			continue
+		}
+
+		receiver := stmt.ReceiverName()
+		if !stmt.ContainsExtraColumns() {
+			receiver = fmt.Sprintf("%s.%s", entityPkg, receiver)
+		}
+		if stmt.Limit.One() {
+			writer.Write("%s *%s", strcase.ToCamel(stmt.FuncName), receiver)
+		} else {
+			writer.Write("%s []*%s", strcase.ToCamel(stmt.FuncName), receiver)
+		}
+	}
+	writer.Write(`}`)
+
+	return writer.String()
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 
-	"github.com/xyzbit/codegen/sqlgen/pkg/parameter"
+	"github.com/iancoleman/strcase"
+
 	"github.com/pingcap/parser/mysql"
+	"github.com/xyzbit/codegen/sqlgen/pkg/parameter"
 )
 
 const (
@@ -17,7 +19,33 @@ const (
 	TypeNullString byte = 0xf2
 )
 
-const defaultThirdDecimalPkg = "github.com/shopspring/decimal"
+// The types which have no corresponding mysql type, e.g. the boolean and array types of postgres.
+const (
+	// TypeBool is the boolean type.
+	TypeBool byte = 0xe0
+	// TypeStringArray is the array type of the text types.
+	TypeStringArray byte = 0xe1
+	// TypeInt64Array is the array type of the integer types.
+	TypeInt64Array byte = 0xe2
+	// TypeFloat64Array is the array type of the float types.
+	TypeFloat64Array byte = 0xe3
+	// TypeBoolArray is the array type of the boolean type.
+	TypeBoolArray byte = 0xe4
+)
+
+const (
+	defaultThirdDecimalPkg = "github.com/shopspring/decimal"
+	defaultThirdArrayPkg   = "github.com/lib/pq"
+)
+
+// thirdPkgs is the third package of the go type of the column type.
+var thirdPkgs = map[byte]string{
+	mysql.TypeNewDecimal: defaultThirdDecimalPkg,
+	TypeStringArray:      defaultThirdArrayPkg,
+	TypeInt64Array:       defaultThirdArrayPkg,
+	TypeFloat64Array:     defaultThirdArrayPkg,
+	TypeBoolArray:        defaultThirdArrayPkg,
+}
 
 type typeKey struct {
 	tp            byte
@@ -65,6 +93,23 @@ var typeMapper = map[typeKey]string{
 	{tp: mysql.TypeVarString}:  "string",
 	{tp: mysql.TypeString}:     "string",
 	{tp: TypeNullString}:       "sql.NullString",
+	{tp: TypeBool}:             "bool",
+	{
+		tp:       TypeStringArray,
+		thirdPkg: defaultThirdArrayPkg,
+	}: "pq.StringArray",
+	{
+		tp:       TypeInt64Array,
+		thirdPkg: defaultThirdArrayPkg,
+	}: "pq.Int64Array",
+	{
+		tp:       TypeFloat64Array,
+		thirdPkg: defaultThirdArrayPkg,
+	}: "pq.Float64Array",
+	{
+		tp:       TypeBoolArray,
+		thirdPkg: defaultThirdArrayPkg,
+	}: "pq.BoolArray",
 
 	// aggregate functions
 	{tp: mysql.TypeTiny, aggregateCall: true}:     "sql.NullInt16",
@@ -103,25 +148,92 @@ var typeMapper = map[typeKey]string{
 	{tp: TypeNullLongLong, aggregateCall: true}:     "sql.NullInt64",
 	{tp: TypeNullDecimal, aggregateCall: true}:      "decimal.NullDecimal",
 	{tp: TypeNullString, aggregateCall: true}:       "sql.NullString",
+	{tp: TypeBool, aggregateCall: true}:             "sql.NullBool",
+}
+
+// NullStrategy is the strategy of the Go type of the nullable column.
+type NullStrategy string
+
+const (
+	// NullNone ignores the nullability, the nullable column uses the same type as the not null column.
+	NullNone NullStrategy = ""
+	// NullPointer uses the pointer type, e.g. *int64.
+	NullPointer NullStrategy = "pointer"
+	// NullSQL uses the sql.Null* type, e.g. sql.NullInt64, the type which
+	// has no corresponding sql.Null* type uses sql.Null[T].
+	NullSQL NullStrategy = "sql"
+	// NullGeneric uses the generic sql.Null[T] type, it requires go1.22.
+	NullGeneric NullStrategy = "generic"
+)
+
+var sqlNullMapper = map[string]string{
+	"int16":           "sql.NullInt16",
+	"int32":           "sql.NullInt32",
+	"int64":           "sql.NullInt64",
+	"uint8":           "sql.NullByte",
+	"byte":            "sql.NullByte",
+	"float64":         "sql.NullFloat64",
+	"string":          "sql.NullString",
+	"bool":            "sql.NullBool",
+	"time.Time":       "sql.NullTime",
+	"decimal.Decimal": "decimal.NullDecimal",
+}
+
+// IsValid returns true if the strategy is supported.
+func (s NullStrategy) IsValid() bool {
+	switch s {
+	case NullNone, NullPointer, NullSQL, NullGeneric:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s NullStrategy) wrap(goType string) string {
+	switch s {
+	case NullPointer:
+		return "*" + goType
+	case NullSQL:
+		if tp, ok := sqlNullMapper[goType]; ok {
+			return tp
+		}
+		return fmt.Sprintf("sql.Null[%s]", goType)
+	case NullGeneric:
+		return fmt.Sprintf("sql.Null[%s]", goType)
+	default:
+		return goType
+	}
 }
 
 // Type is the type of the column.
 type Type byte
 
-// DataType returns the Go type, third-package of the column.
+// DataType returns the Go type, third-package of the column, the overridden Go type
+// is used by the entity and the parameters.
 func (c Column) DataType() (parameter.Parameter, error) {
+	if o := c.TypeOverride; o != nil && !c.AggregateCall {
+		goType := o.GoType
+		if c.Nullable() {
+			goType = c.NullStrategy.wrap(goType)
+		}
+		return parameter.Parameter{Column: strcase.ToCamel(c.Name), Type: goType, ThirdPkg: o.Import, Converter: c.converter(false)}, nil
+	}
+
 	key := typeKey{tp: c.TP, signed: c.Unsigned, aggregateCall: c.AggregateCall}
 	if c.AggregateCall {
 		key = typeKey{tp: c.TP, aggregateCall: c.AggregateCall}
 	}
-	if c.TP == mysql.TypeNewDecimal {
-		key.thirdPkg = defaultThirdDecimalPkg
+	if pkg, ok := thirdPkgs[c.TP]; ok {
+		key.thirdPkg = pkg
 	}
 
 	goType, ok := typeMapper[key]
 	if !ok {
 		return parameter.Parameter{}, fmt.Errorf("unsupported type: %v", c.TP)
 	}
+	if c.Nullable() {
+		goType = c.NullStrategy.wrap(goType)
+	}
 
 	return NewParameter(c.Name, goType, key.thirdPkg), nil
 }
@@ -132,6 +244,57 @@ func (c Column) GoType() (string, error) {
 	return p.Type, err
 }
 
+// POGoType returns the Go type of the column in the persistent object and the
+// query result, it is the default Go type if the overridden Go type is converted.
+func (c Column) POGoType() (string, error) {
+	if c.TypeOverride.Converted() {
+		c.TypeOverride = nil
+	}
+	return c.GoType()
+}
+
+// ToPO returns the expression which converts the field of the entity v to the
+// field of the persistent object.
+func (c Column) ToPO(v string) string {
+	field := v + "." + strcase.ToCamel(c.Name)
+	if !c.TypeOverride.Converted() {
+		return field
+	}
+	return fmt.Sprintf("%s(%s)", c.TypeOverride.ToDB, field)
+}
+
+// ToEntity returns the expression which converts the field of the persistent
+// object v to the field of the entity.
+func (c Column) ToEntity(v string) string {
+	field := v + "." + strcase.ToCamel(c.Name)
+	if !c.TypeOverride.Converted() {
+		return field
+	}
+	return fmt.Sprintf("%s(%s)", c.TypeOverride.FromDB, field)
+}
+
+// converter returns the format which converts the parameter of the column to the
+// query argument, the elements of the slice parameter are converted one by one.
+func (c Column) converter(slice bool) string {
+	if !c.TypeOverride.Converted() {
+		return ""
+	}
+	if !slice {
+		return c.TypeOverride.ToDB + "(%s)"
+	}
+	dbType, err := c.POGoType()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("lo.Map(%%s, func(v %s, _ int) %s { return %s(v) })", c.TypeOverride.GoType, dbType, c.TypeOverride.ToDB)
+}
+
+// Nullable returns true if the Go type of the column should represent NULL,
+// the aggregate call and the extension null types are already nullable.
+func (c Column) Nullable() bool {
+	return c.NullStrategy != NullNone && !c.NotNull && !c.AggregateCall && !isNullType(c.TP)
+}
+
 func (c Column) HasComment() bool {
 	return len(c.Comment) > 0
 }
@@ -15,12 +15,34 @@ const (
 	MockDocker = "docker"
 )
 
+const (
+	// DialectMySQL mysql 方言, 为默认值
+	DialectMySQL = "mysql"
+	// DialectPostgres postgres 方言
+	DialectPostgres = "postgres"
+	// DialectSQLite sqlite 方言
+	DialectSQLite = "sqlite"
+)
+
+const (
+	// LayoutSplit 生成的代码保存在 _gen.go 文件中, 自定义代码保存在脚手架文件中
+	LayoutSplit = "split"
+)
+
 // RunArg 代表运行参数，同时也用于配置文件的解析
 type RunArg struct {
 	// DSN 数据库连接字符串
 	DSN string `yaml:"dsn"`
 	// Filename SQL文件模式
 	Filename []string `yaml:"filename"`
+	// Migrations 迁移文件目录, 按文件名顺序回放其中的 DDL 得到最终的表结构, 仅支持 mysql 方言
+	Migrations string `yaml:"migrations"`
+	// Tolerant 宽松模式, 忽略 SQL 文件中与表结构无关的语句 (如 SET, LOCK TABLES, CREATE TRIGGER) 并输出警告,
+	// 用于解析 mysqldump --no-data 的输出, 仅支持 mysql 方言
+	Tolerant bool `yaml:"tolerant"`
+	// Dialect SQL 文件和数据库的方言: mysql, postgres, sqlite, 为空时为 mysql,
+	// 以 postgres:// 开头的 dsn 为 postgres, 以 file: 开头或以 .db, .sqlite, .sqlite3 结尾的 dsn 为 sqlite
+	Dialect string `yaml:"dialect"`
 	// Table 要生成的表名模式
 	Table []string `yaml:"table"`
 	// Mode 生成模式（仅用于命令行）
@@ -35,10 +57,98 @@ type RunArg struct {
 	RepoPackage string `yaml:"repo_package"`
 	// EntityPackage 实体包名
 	EntityPackage string `yaml:"entity_package"`
-	// AutoAudit 是否开启自动审计
+	// AutoAudit 是否开启自动审计, 开启后创建和更新时填充表中存在的审计列
 	AutoAudit bool `yaml:"auto_audit"`
+	// Audit 审计列的列名和获取操作人的函数, 开启自动审计时生效
+	Audit Audit `yaml:"audit"`
+	// SoftDelete 软删除列的配置, 表中存在软删除列时 Delete 为软删除, 仅支持 gorm
+	SoftDelete SoftDelete `yaml:"soft_delete"`
+	// OptimisticLock 乐观锁版本列的配置, 表中存在版本列时更新需要匹配版本
+	OptimisticLock OptimisticLock `yaml:"optimistic_lock"`
+	// TypeOverrides 按顺序匹配的列类型覆盖规则, 每列使用第一个匹配的规则
+	TypeOverrides []TypeOverride `yaml:"type_overrides"`
 	// MockTypes 要生成的 mock 类型
 	MockTypes []string `yaml:"mock_types"`
+	// Nullable 可为 NULL 的列的类型策略: pointer, sql, generic, 为空时与非空列类型相同
+	Nullable string `yaml:"nullable"`
+	// Layout 生成文件的布局: 为空时生成的代码与自定义代码在同一个文件中, split 时分离到 _gen.go 文件
+	Layout string `yaml:"layout"`
+	// TemplatesDir 自定义模版目录, 其中的同名模版会覆盖内置模版
+	TemplatesDir string `yaml:"templates_dir"`
+	// DryRun 只输出将要创建、覆盖、合并或跳过的文件, 不写入文件（仅用于命令行）
+	DryRun bool `yaml:"-"`
+	// Diff 输出已存在的文件与生成内容之间的 unified diff, 不写入文件（仅用于命令行）
+	Diff bool `yaml:"-"`
+	// Check 只在内存中生成代码, 存在与生成结果不一致的文件时返回错误, 用于 CI 检查（仅用于命令行）
+	Check bool `yaml:"-"`
+	// Adopt 接管没有基线的存量文件: 用生成的内容覆盖并保存基线（仅用于命令行）
+	Adopt bool `yaml:"-"`
+}
+
+// Audit 代表自动审计的配置, 列名为空时使用默认的列名
+type Audit struct {
+	// Creator 创建人的列名, 默认为 creator
+	Creator string `yaml:"creator"`
+	// Updater 更新人的列名, 默认为 operator
+	Updater string `yaml:"updater"`
+	// CreatedTime 创建时间的列名, 默认为 created_time
+	CreatedTime string `yaml:"created_time"`
+	// UpdatedTime 更新时间的列名, 默认为 updated_time
+	UpdatedTime string `yaml:"updated_time"`
+	// OperatorFunc 从 context 中获取操作人的函数, 格式为 完整包名.函数名, 签名为 func(context.Context) string,
+	// 为空时生成返回空字符串的默认实现, 可以在初始化时替换
+	OperatorFunc string `yaml:"operator_func"`
+}
+
+// WithDefault 返回填充了默认列名的配置
+func (a Audit) WithDefault() Audit {
+	if a.Creator == "" {
+		a.Creator = "creator"
+	}
+	if a.Updater == "" {
+		a.Updater = "operator"
+	}
+	if a.CreatedTime == "" {
+		a.CreatedTime = "created_time"
+	}
+	if a.UpdatedTime == "" {
+		a.UpdatedTime = "updated_time"
+	}
+	return a
+}
+
+// SoftDelete 代表软删除的配置
+type SoftDelete struct {
+	// Columns 软删除的列名, 使用表中第一个存在且类型符合的列, 为空时为 deleted_at 和 is_deleted
+	Columns []string `yaml:"columns"`
+	// Tables 按表名配置软删除的列名, 优先于 Columns, 列名为空时该表不使用软删除
+	Tables map[string]string `yaml:"tables"`
+}
+
+// OptimisticLock 代表乐观锁的配置
+type OptimisticLock struct {
+	// Columns 版本列的列名, 使用表中第一个存在且类型符合的列, 为空时为 version 和 revision
+	Columns []string `yaml:"columns"`
+	// Tables 按表名配置版本列的列名, 优先于 Columns, 列名为空时该表不使用乐观锁
+	Tables map[string]string `yaml:"tables"`
+}
+
+// TypeOverride 代表列的 go 类型覆盖规则, 配置的匹配条件需要全部满足
+type TypeOverride struct {
+	// DBType 匹配列声明的数据库类型, 不区分大小写, 如 tinyint(1), 不带长度时匹配任意长度, 如 json
+	DBType string `yaml:"db_type"`
+	// Column 按 glob 匹配 表名.列名, 如 *.status, orders.amount
+	Column string `yaml:"column"`
+	// ColumnRegex 按正则匹配列名
+	ColumnRegex string `yaml:"column_regex"`
+	// GoType 列的 go 类型, 如 uuid.UUID
+	GoType string `yaml:"go_type"`
+	// Import go 类型的导入路径, 如 github.com/google/uuid
+	Import string `yaml:"import"`
+	// ToDB 将 go 类型转换为列默认 go 类型的函数, 为空时 go 类型直接用于读写数据库
+	ToDB string `yaml:"to_db"`
+	// FromDB 将列默认 go 类型转换为 go 类型的函数, 与 ToDB 同时配置
+	FromDB string `yaml:"from_db"`
 }
 
 // DefaultRunArg 返回默认运行参数